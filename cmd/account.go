@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/harrybrwn/apizza/cmd/cli"
+	"github.com/harrybrwn/apizza/cmd/client"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/cmd/internal/out"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
+)
+
+// `apizza account`
+type accountCmd struct {
+	cli.CliCommand
+	client.UserFinder
+
+	json  bool
+	limit int
+}
+
+// NewAccountCmd creates the 'account' command.
+func NewAccountCmd(b cli.Builder, in io.Reader) cli.CliCommand {
+	c := &accountCmd{
+		UserFinder: client.NewUserGetter(b, in),
+		json:       false,
+		limit:      5,
+	}
+	c.CliCommand = b.Build("account", "View your Dominos account.", c)
+	c.Cmd().Long = `The account command signs in to your Dominos account and shows
+the data that Dominos has saved for it.
+
+The email in the config file is used as the account username. The password
+is read from $` + client.PasswordEnv + ` or prompted for.`
+	c.Cmd().PersistentFlags().BoolVar(&c.json, "json", c.json, "print the output as json")
+
+	history := b.Build("history", "Show the account's previous orders", cli.RunFunction(c.history))
+	history.Flags().IntVarP(&c.limit, "limit", "n", c.limit, "the number of previous orders to show")
+
+	c.Addcmd(
+		b.Build("profile", "Show the account profile", cli.RunFunction(c.profile)),
+		b.Build("addresses", "Show the addresses saved on the account", cli.RunFunction(c.addresses)),
+		b.Build("loyalty", "Show the account's reward points and coupons", cli.RunFunction(c.loyalty)),
+		history,
+	)
+	return c
+}
+
+// Run will show the account profile.
+func (c *accountCmd) Run(cmd *cobra.Command, args []string) error {
+	return c.profile(cmd, args)
+}
+
+func (c *accountCmd) profile(cmd *cobra.Command, args []string) error {
+	user, err := c.User()
+	if err != nil {
+		return err
+	}
+	return printProfile(c.Output(), user, c.json)
+}
+
+func (c *accountCmd) addresses(cmd *cobra.Command, args []string) error {
+	user, err := c.User()
+	if err != nil {
+		return err
+	}
+	return printUserAddresses(c.Output(), user.Addresses, c.json)
+}
+
+func (c *accountCmd) loyalty(cmd *cobra.Command, args []string) error {
+	user, err := c.User()
+	if err != nil {
+		return err
+	}
+	loyalty, err := user.Loyalty()
+	if err != nil {
+		return err
+	}
+	return printLoyalty(c.Output(), loyalty, c.json)
+}
+
+func (c *accountCmd) history(cmd *cobra.Command, args []string) error {
+	user, err := c.User()
+	if err != nil {
+		return err
+	}
+	orders, err := user.PreviousOrders(c.limit)
+	if err != nil {
+		return err
+	}
+	return printAccountHistory(c.Output(), orders, c.json)
+}
+
+func printJSON(w io.Writer, v interface{}) error {
+	raw, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", raw)
+	return err
+}
+
+func newTabWriter(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+}
+
+func printProfile(w io.Writer, u *dawg.UserProfile, asJSON bool) error {
+	if asJSON {
+		return printJSON(w, u)
+	}
+	tw := newTabWriter(w)
+	fmt.Fprintf(tw, "Name:\t%s %s\n", u.FirstName, u.LastName)
+	fmt.Fprintf(tw, "Email:\t%s\n", u.Email)
+	fmt.Fprintf(tw, "Phone:\t%s\n", u.Phone)
+	fmt.Fprintf(tw, "Customer ID:\t%s\n", u.CustomerID)
+	fmt.Fprintf(tw, "Email updates:\t%s\n", out.YesNo(u.EmailOptIn))
+	fmt.Fprintf(tw, "Sms updates:\t%s\n", out.YesNo(u.SmsOptIn))
+	fmt.Fprintf(tw, "Addresses:\t%d\n", len(u.Addresses))
+	return tw.Flush()
+}
+
+func printUserAddresses(w io.Writer, addrs []*dawg.UserAddress, asJSON bool) error {
+	if asJSON {
+		return printJSON(w, addrs)
+	}
+	if len(addrs) == 0 {
+		_, err := fmt.Fprintln(w, "No addresses saved.")
+		return err
+	}
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "NAME\tSTREET\tCITY\tREGION\tPOSTAL CODE\tDEFAULT")
+	for _, a := range addrs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			a.Name, a.LineOne(), a.City(), a.StateCode(), a.Zip(), out.YesNo(a.IsDefault))
+	}
+	return tw.Flush()
+}
+
+func printLoyalty(w io.Writer, l *dawg.CustomerLoyalty, asJSON bool) error {
+	if asJSON {
+		return printJSON(w, l)
+	}
+	tw := newTabWriter(w)
+	fmt.Fprintf(tw, "Points:\t%d\n", l.VestedPointBalance)
+	fmt.Fprintf(tw, "Pending points:\t%s\n", l.PendingPointBalance)
+	fmt.Fprintf(tw, "Status:\t%s\n", l.AccountStatus)
+	fmt.Fprintf(tw, "Points expire:\t%s\n", l.BasePointExpirationDate)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if len(l.LoyaltyCoupons) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(w, "\nRewards:")
+	tw = newTabWriter(w)
+	fmt.Fprintln(tw, "  CODE\tPOINTS\tLIMIT PER ORDER\tAVAILABLE")
+	for _, c := range l.LoyaltyCoupons {
+		fmt.Fprintf(tw, "  %s\t%d\t%s\t%s\n",
+			c.CouponCode, c.PointValue, c.LimitPerOrder,
+			out.YesNo(c.PointValue <= l.VestedPointBalance))
+	}
+	return tw.Flush()
+}
+
+func printAccountHistory(w io.Writer, orders []*dawg.EasyOrder, asJSON bool) error {
+	if asJSON {
+		return printJSON(w, orders)
+	}
+	if len(orders) == 0 {
+		_, err := fmt.Fprintln(w, "No previous orders.")
+		return err
+	}
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "#\tDATE\tSTORE\tMETHOD\tPRODUCTS\tTOTAL")
+	for i, eo := range orders {
+		o := eo.Order
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n",
+			i, o.PlaceOrderTime, o.StoreID, o.ServiceMethod,
+			productList(o.Products), o.Amounts["Customer"].Decimal())
+	}
+	return tw.Flush()
+}
+
+func productList(products []*dawg.OrderProduct) string {
+	codes := make([]string, 0, len(products))
+	for _, p := range products {
+		if p.Qty > 1 {
+			codes = append(codes, fmt.Sprintf("%dx %s", p.Qty, p.Code))
+		} else {
+			codes = append(codes, p.Code)
+		}
+	}
+	return strings.Join(codes, ", ")
+}
+
+// `apizza reorder`
+type reorderCmd struct {
+	cli.CliCommand
+	client.UserFinder
+	db         *cache.DataBase
+	getaddress func() dawg.Address
+	market     func() *dawg.Market
+
+	easy bool
+	name string
+}
+
+// NewReorderCmd creates the 'reorder' command.
+func NewReorderCmd(b cli.Builder, in io.Reader) cli.CliCommand {
+	c := &reorderCmd{
+		UserFinder: client.NewUserGetter(b, in),
+		db:         b.DB(),
+		getaddress: b.Address,
+		market:     func() *dawg.Market { return b.Config().GetMarket() },
+		easy:       false,
+	}
+	c.CliCommand = b.Build("reorder [--easy | <history index>]",
+		"Copy a previous order from your account into the cart.", c)
+	c.Cmd().Long = `The reorder command takes either the account's easy order or a
+previous order (the index shown by 'apizza account history') and saves it
+in the cart so that it can be edited or sent with 'apizza order'.
+
+Items that are no longer on the store's menu are left out of the new order.`
+	c.Flags().BoolVar(&c.easy, "easy", c.easy, "reorder the account's easy order")
+	c.Flags().StringVarP(&c.name, "name", "n", "", "set the name of the new order")
+	return c
+}
+
+func (c *reorderCmd) Run(cmd *cobra.Command, args []string) error {
+	if c.easy == (len(args) == 1) || len(args) > 1 {
+		return errors.New("give either --easy or one history index")
+	}
+	user, err := c.User()
+	if err != nil {
+		return err
+	}
+
+	var (
+		eo   *dawg.EasyOrder
+		name string
+	)
+	if c.easy {
+		if eo, err = user.GetEasyOrder(); err != nil {
+			return err
+		}
+		if eo == nil {
+			return errors.New("no easy order saved on the account")
+		}
+		name = "easyorder"
+	} else {
+		i, err := strconv.Atoi(args[0])
+		if err != nil || i < 0 {
+			return fmt.Errorf("bad history index '%s'", args[0])
+		}
+		orders, err := user.PreviousOrders(i + 1)
+		if err != nil {
+			return err
+		}
+		if i >= len(orders) {
+			return fmt.Errorf("no order at history index %d", i)
+		}
+		eo = orders[i]
+		name = fmt.Sprintf("reorder-%d", i)
+	}
+
+	var addr dawg.Address = c.getaddress()
+	if eo.Order.Address != nil {
+		addr = eo.Order.Address
+	}
+	store, err := c.market().NewStore(eo.Order.StoreID, eo.Order.ServiceMethod, addr)
+	if err != nil {
+		return err
+	}
+	order, err := eo.ToOrder(store)
+	if missing, ok := err.(*dawg.ItemsNotFoundError); ok {
+		c.Printf("Warning: %s\n", missing)
+	} else if err != nil {
+		return err
+	}
+
+	if c.name != "" {
+		order.SetName(c.name)
+	} else if order.Name() == "" {
+		order.SetName(name)
+	}
+	return data.SaveOrder(order, c.Output(), c.db)
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/cmd/internal/obj"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestPrintProfile(t *testing.T) {
+	tests.InitHelpers(t)
+	user := &dawg.UserProfile{
+		FirstName:  "Bob",
+		LastName:   "Smith",
+		Email:      "bob@example.com",
+		Phone:      "1231231234",
+		CustomerID: "123",
+		EmailOptIn: true,
+		Addresses: []*dawg.UserAddress{
+			{Name: "home", Street: "1600 Pennsylvania Ave NW", CityName: "Washington",
+				Region: "DC", PostalCode: "20500", IsDefault: true},
+		},
+	}
+	buf := &bytes.Buffer{}
+	tests.Check(printProfile(buf, user, false))
+	tests.Compare(t, buf.String(), `Name:           Bob Smith
+Email:          bob@example.com
+Phone:          1231231234
+Customer ID:    123
+Email updates:  yes
+Sms updates:    no
+Addresses:      1
+`)
+	buf.Reset()
+	tests.Check(printUserAddresses(buf, user.Addresses, false))
+	tests.Compare(t, buf.String(), `NAME  STREET                    CITY        REGION  POSTAL CODE  DEFAULT
+home  1600 Pennsylvania Ave NW  Washington  DC      20500        yes
+`)
+	buf.Reset()
+	tests.Check(printProfile(buf, user, true))
+	u := dawg.UserProfile{}
+	tests.Check(json.Unmarshal(buf.Bytes(), &u))
+	tests.StrEq(u.CustomerID, user.CustomerID, "wrong customer id from json output")
+}
+
+func TestPrintLoyalty(t *testing.T) {
+	tests.InitHelpers(t)
+	l := &dawg.CustomerLoyalty{VestedPointBalance: 70, AccountStatus: "ACTIVE", PendingPointBalance: "10"}
+	l.LoyaltyCoupons = append(l.LoyaltyCoupons,
+		dawg.LoyaltyCoupon{CouponCode: "8000", PointValue: 60, LimitPerOrder: "1"},
+		dawg.LoyaltyCoupon{CouponCode: "8001", PointValue: 120, LimitPerOrder: "1"},
+	)
+	buf := &bytes.Buffer{}
+	tests.Check(printLoyalty(buf, l, false))
+	tests.Compare(t, buf.String(), `Points:          70
+Pending points:  10
+Status:          ACTIVE
+Points expire:   
+
+Rewards:
+  CODE  POINTS  LIMIT PER ORDER  AVAILABLE
+  8000  60      1                yes
+  8001  120     1                no
+`)
+}
+
+func TestPrintAccountHistory(t *testing.T) {
+	tests.InitHelpers(t)
+	buf := &bytes.Buffer{}
+	tests.Check(printAccountHistory(buf, nil, false))
+	tests.Compare(t, buf.String(), "No previous orders.\n")
+	buf.Reset()
+
+	eo := &dawg.EasyOrder{}
+	eo.Order.PlaceOrderTime = "2020-04-01 18:30:00"
+	eo.Order.StoreID = "4336"
+	eo.Order.ServiceMethod = dawg.Carryout
+	eo.Order.Amounts = map[string]dawg.Money{"Customer": dawg.Cents(2015)}
+	eo.Order.Products = []*dawg.OrderProduct{
+		{ItemCommon: dawg.ItemCommon{Code: "14SCREEN"}, Qty: 2},
+		{ItemCommon: dawg.ItemCommon{Code: "20BCOKE"}, Qty: 1},
+	}
+	tests.Check(printAccountHistory(buf, []*dawg.EasyOrder{eo}, false))
+	tests.Compare(t, buf.String(), `#  DATE                 STORE  METHOD    PRODUCTS              TOTAL
+0  2020-04-01 18:30:00  4336   Carryout  2x 14SCREEN, 20BCOKE  20.15
+`)
+}
+
+func TestAddressSync(t *testing.T) {
+	tests.InitHelpers(t)
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	db := r.DB()
+
+	raw, err := obj.AsGob(cmdtest.TestAddress())
+	tests.Check(err)
+	tests.Check(db.WithBucket("addresses").Put("home", raw))
+	raw, err = obj.AsGob(&obj.Address{Street: "1 Main St", CityName: "Springfield", State: "IL", Zipcode: "62701"})
+	tests.Check(err)
+	tests.Check(db.WithBucket("addresses").Put("work", raw))
+
+	user := &dawg.UserProfile{Addresses: []*dawg.UserAddress{
+		{Name: "work", Street: "500 Old Rd", CityName: "Springfield", Region: "IL", PostalCode: "62702",
+			DeliveryInstructions: "leave it with the front desk"},
+		{Street: "42 Elm St", CityName: "Springfield", Region: "IL", PostalCode: "62703"},
+	}}
+	tests.Check(pushAddresses(db, user))
+	if len(user.Addresses) != 3 {
+		t.Fatalf("expected 3 account addresses; got %d", len(user.Addresses))
+	}
+	work := user.Addresses[0]
+	tests.StrEq(work.LineOne(), "1 Main St", "existing account address should be updated")
+	tests.StrEq(work.StreetNumber, "1", "street number should be updated")
+	tests.StrEq(work.PostalCode, "62701", "zip should be updated")
+	tests.StrEq(work.DeliveryInstructions, "leave it with the front desk", "delivery instructions should be kept")
+	tests.StrEq(user.Addresses[2].Name, "home", "new addresses should keep their name")
+
+	tests.Check(pullAddresses(db, user))
+	m, err := db.WithBucket("addresses").Map()
+	tests.Check(err)
+	if len(m) != 3 {
+		t.Fatalf("expected 3 named addresses; got %d", len(m))
+	}
+	addr, err := obj.FromGob(m["42 Elm St"])
+	tests.Check(err)
+	tests.StrEq(addr.Zip(), "62703", "unnamed address should be saved under its street")
+}
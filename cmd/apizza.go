@@ -16,12 +16,14 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
 	fp "path/filepath"
 	"strings"
 
 	"github.com/harrybrwn/apizza/cmd/cli"
+	"github.com/harrybrwn/apizza/cmd/client"
 	"github.com/harrybrwn/apizza/cmd/command"
 	"github.com/harrybrwn/apizza/pkg/config"
 	"github.com/spf13/cobra"
@@ -47,6 +49,12 @@ func AllCommands(builder cli.Builder) []*cobra.Command {
 		NewMenuCmd(builder).Cmd(),
 		NewOrderCmd(builder).Cmd(),
 		NewAddAddressCmd(builder, os.Stdin).Cmd(),
+		NewAccountCmd(builder, os.Stdin).Cmd(),
+		NewReorderCmd(builder, os.Stdin).Cmd(),
+		NewHistoryCmd(builder).Cmd(),
+		NewSpendCmd(builder).Cmd(),
+		NewScheduleCmd(builder).Cmd(),
+		NewLogsCmd(builder).Cmd(),
 		command.NewCompletionCmd(builder),
 	}
 }
@@ -96,15 +104,15 @@ func Execute(args []string, dir string) (msg *ErrMsg) {
 
 var test = false
 
-func yesOrNo(in *os.File, msg string) bool {
-	var res string
+func yesOrNo(in io.Reader, msg string) bool {
 	fmt.Printf("%s ", msg)
-	_, err := fmt.Fscan(in, &res)
+	// use the same reader as the password prompts so no input is lost
+	line, err := client.Prompt(in).Line("")
 	if err != nil {
 		return false
 	}
 
-	switch strings.ToLower(res) {
+	switch strings.ToLower(strings.TrimSpace(line)) {
 	case "y", "yes", "si":
 		return true
 	}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/harrybrwn/apizza/cmd/cli"
 	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/cmd/internal/obj"
 	"github.com/harrybrwn/apizza/pkg/config"
 	"github.com/harrybrwn/apizza/pkg/errs"
 	"github.com/harrybrwn/apizza/pkg/tests"
@@ -247,3 +248,22 @@ func TestYesOrNo(t *testing.T) {
 	}
 	tests.Check(f.Close())
 }
+
+func TestAppAddressFlag(t *testing.T) {
+	tests.InitHelpers(t)
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	a := CreateApp(r.ToApp())
+
+	a.gOpts.Address = "123 Main St Apt 4, Springfield, IL 62704-1234"
+	tests.Check(a.prerun(a.Cmd(), []string{}))
+	addr := a.Address()
+	tests.StrEq(addr.LineOne(), "123 Main St", "wrong street")
+	tests.StrEq(addr.City(), "Springfield", "wrong city")
+	tests.StrEq(addr.Zip(), "62704-1234", "wrong zip code")
+	tests.StrEq(obj.UnitFmt(addr), "Apartment 4", "the unit should be kept")
+
+	a.addr = nil
+	a.gOpts.Address = "123 Main St, Springfield, IL"
+	tests.Exp(a.prerun(a.Cmd(), []string{}), "expected an error for an address with no zip code")
+}
@@ -46,7 +46,7 @@ func NewApp(out io.Writer) *App {
 		opts:  opts.ApizzaFlags{},
 	}
 	app.CliCommand = cli.NewCommand("apizza", "Dominos pizza from the command line.", app.Run)
-	app.StoreFinder = client.NewStoreGetterFunc(app.getService, app.Address)
+	app.StoreFinder = client.NewStoreGetterFunc(app.DB, app.getMarket, app.getService, app.Address)
 	app.SetOutput(out)
 	return app
 }
@@ -117,11 +117,16 @@ func (a *App) Cleanup() (err error) {
 	return errs.Pair(a.db.Close(), config.Save())
 }
 
-func (a *App) getService() string {
+func (a *App) getService() dawg.ServiceMethod {
 	if len(a.gOpts.Service) == 0 {
 		return a.conf.Service
 	}
-	return a.gOpts.Service
+	m, _ := dawg.ParseServiceMethod(a.gOpts.Service)
+	return m
+}
+
+func (a *App) getMarket() *dawg.Market {
+	return a.conf.GetMarket()
 }
 
 var _ cli.Builder = (*App)(nil)
@@ -181,8 +186,9 @@ func (a *App) initflags() {
 }
 
 func (a *App) prerun(*cobra.Command, []string) (err error) {
+	dawg.SetOffline(a.gOpts.Offline)
 	if a.gOpts.ResetMenu {
-		err = a.DB().Delete("menu")
+		err = data.DeleteMenus(a.DB())
 	}
 	var e error
 	if a.gOpts.Address != "" {
@@ -203,11 +209,23 @@ func (a *App) prerun(*cobra.Command, []string) (err error) {
 		}
 	}
 
+	if a.conf.Card.Number != "" {
+		fmt.Fprintln(os.Stderr, "Warning: the config file has a plaintext card number, "+
+			"run 'apizza config card show' to move it into the encrypted card vault")
+	}
+
+	if a.conf.Market != "" {
+		if _, err := dawg.GetMarket(a.conf.Market); err != nil {
+			return err
+		}
+	}
+
 	if a.gOpts.Service != "" {
-		if !(a.gOpts.Service == dawg.Delivery || a.gOpts.Service == dawg.Carryout) {
-			return dawg.ErrBadService
+		m, err := dawg.ParseServiceMethod(a.gOpts.Service)
+		if err != nil {
+			return err
 		}
-		a.conf.Service = a.gOpts.Service
+		a.conf.Service = m
 	}
 
 	if a.gOpts.LogFile != "" {
@@ -18,9 +18,9 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -40,16 +40,20 @@ type cartCmd struct {
 	cli.CliCommand
 	data.MenuCacher
 	client.StoreFinder
-	db *cache.DataBase
+	client.UserFinder
+	db     *cache.DataBase
+	market func() *dawg.Market
 
-	validate bool
-	price    bool
-	delete   bool
-	verbose  bool
+	validate  bool
+	price     bool
+	delete    bool
+	verbose   bool
+	nutrition bool
 
 	add     []string
 	remove  string // yes, you can only remove one thing at a time
 	product string
+	redeem  string
 
 	topping bool // not actually a flag anymore
 }
@@ -82,6 +86,7 @@ func (c *cartCmd) Run(cmd *cobra.Command, args []string) (err error) {
 	if order, err = data.GetOrder(name, c.db); err != nil {
 		return err
 	}
+	order.SetMarket(c.market())
 	order.Address = dawg.StreetAddrFromAddress(c.Address())
 
 	if c.validate {
@@ -94,6 +99,18 @@ func (c *cartCmd) Run(cmd *cobra.Command, args []string) (err error) {
 		return nil
 	}
 
+	if c.redeem != "" {
+		user, err := c.User()
+		if err != nil {
+			return err
+		}
+		order.SetUser(user)
+		if err = order.RedeemLoyalty(c.redeem); err != nil {
+			return err
+		}
+		return data.SaveOrder(order, c.Output(), c.db)
+	}
+
 	if len(c.remove) > 0 {
 		if c.topping {
 			for _, p := range order.Products {
@@ -127,7 +144,7 @@ func (c *cartCmd) Run(cmd *cobra.Command, args []string) (err error) {
 				}
 			}
 		} else {
-			if err := c.db.UpdateTS("menu", c); err != nil {
+			if err := c.UpdateMenu(); err != nil {
 				return err
 			}
 			menu := c.Menu()
@@ -145,7 +162,27 @@ func (c *cartCmd) Run(cmd *cobra.Command, args []string) (err error) {
 		}
 		return data.SaveOrder(order, c.Output(), c.db)
 	}
-	return out.PrintOrder(order, true, c.price)
+	if c.price && dawg.IsOffline() {
+		err = c.printEstimate(order)
+	} else {
+		err = out.PrintOrder(order, true, c.price)
+	}
+	if err != nil || !c.nutrition {
+		return err
+	}
+	return out.PrintOrderNutrition(order.Nutrition())
+}
+
+// printEstimate prints the order with a price estimated from the cached menu.
+func (c *cartCmd) printEstimate(order *dawg.Order) error {
+	if err := c.UpdateMenu(); err != nil {
+		return err
+	}
+	price, err := c.Menu().EstimatePrice(order)
+	if err != nil {
+		return err
+	}
+	return out.PrintOrderEstimate(order, price)
 }
 
 func (c *cartCmd) syncWithConfig(o *dawg.Order) error {
@@ -207,6 +244,7 @@ func getOrderItem(order *dawg.Order, code string) dawg.Item {
 func NewCartCmd(b cli.Builder) cli.CliCommand {
 	c := &cartCmd{
 		db:      b.DB(),
+		market:  func() *dawg.Market { return b.Config().GetMarket() },
 		price:   false,
 		delete:  false,
 		verbose: false,
@@ -216,11 +254,12 @@ func NewCartCmd(b cli.Builder) cli.CliCommand {
 	if app, ok := b.(*App); ok {
 		c.StoreFinder = app
 	} else {
-		c.StoreFinder = client.NewStoreGetterFunc(
-			func() string { return b.Config().Service }, b.Address)
+		c.StoreFinder = client.NewStoreGetterFunc(b.DB, c.market,
+			func() dawg.ServiceMethod { return b.Config().Service }, b.Address)
 	}
 
 	c.MenuCacher = data.NewMenuCacher(menuUpdateTime, b.DB(), c.Store)
+	c.UserFinder = client.NewUserGetter(b, os.Stdin)
 	c.CliCommand = b.Build("cart <order name>", "Manage user created orders", c)
 	cmd := c.Cmd()
 
@@ -231,11 +270,13 @@ created orders.`
 
 	c.Flags().BoolVar(&c.validate, "validate", c.validate, "send an order to the dominos order-validation endpoint.")
 	c.Flags().BoolVar(&c.price, "price", c.price, "show to price of an order")
+	c.Flags().BoolVar(&c.nutrition, "nutrition", false, "show the calories and allergens in an order")
 	c.Flags().BoolVarP(&c.delete, "delete", "d", c.delete, "delete the order from the database")
 
 	c.Flags().StringSliceVarP(&c.add, "add", "a", c.add, "add any number of products to a specific order")
 	c.Flags().StringVarP(&c.remove, "remove", "r", c.remove, "remove a product from the order")
 	c.Flags().StringVarP(&c.product, "product", "p", "", "give the product that will be effected by --add or --remove")
+	c.Flags().StringVar(&c.redeem, "redeem", "", "redeem a loyalty reward coupon using your account's points (see 'apizza account loyalty')")
 
 	c.Flags().BoolVarP(&c.verbose, "verbose", "v", c.verbose, "print cart verbosely")
 
@@ -311,10 +352,16 @@ func newAddOrderCmd(b cli.Builder) cli.CliCommand {
 // `apizza order`
 type orderCmd struct {
 	cli.CliCommand
-	db *cache.DataBase
+	client.UserFinder
+	client.CardFinder
+	db   *cache.DataBase
+	conf *cli.Config
 
-	verbose bool
-	track   bool
+	verbose    bool
+	track      bool
+	force      bool
+	overBudget bool
+	placements bool
 
 	email, phone string
 	fname, lname string
@@ -322,11 +369,25 @@ type orderCmd struct {
 	number       string
 	expiration   string
 
+	at           string
+	tip          string
+	instructions string
+
+	carMake, carColor string
+
 	logonly    bool
 	getaddress func() dawg.Address
+	price      func(*dawg.Order) (dawg.Money, error)
+	store      func(id string) (*dawg.Store, error)
 }
 
+// the format of the --at flag
+const futureTimeFmt = "2006-01-02 15:04"
+
 func (c *orderCmd) Run(cmd *cobra.Command, args []string) (err error) {
+	if c.placements {
+		return c.printPlacements()
+	}
 	if len(args) < 1 {
 		return data.PrintOrders(c.db, c.Output(), c.verbose)
 	} else if len(args) > 1 {
@@ -340,44 +401,49 @@ func (c *orderCmd) Run(cmd *cobra.Command, args []string) (err error) {
 	if err != nil {
 		return err
 	}
-
-	order.AddCard(dawg.NewCard(
-		eitherOr(c.number, config.GetString("card.number")),
-		eitherOr(c.expiration, config.GetString("card.expiration")),
-		c.cvv))
-
-	names := strings.Split(config.GetString("name"), " ")
-	if len(names) >= 1 {
-		order.FirstName = eitherOr(c.fname, names[0])
+	if err = c.fill(order); err != nil {
+		return err
 	}
-	if len(names) >= 2 {
-		order.LastName = eitherOr(c.lname, strings.Join(names[1:], " "))
+	if err = c.schedule(order); err != nil {
+		return err
 	}
-	order.Email = eitherOr(c.email, config.GetString("email"))
-	order.Phone = eitherOr(c.phone, config.GetString("phone"))
-	order.Address = dawg.StreetAddrFromAddress(c.getaddress())
 
-	c.Printf("Ordering dominos for %s to %s\n\n", order.ServiceMethod, strings.Replace(obj.AddressFmt(order.Address), "\n", " ", -1))
+	c.Printf("Ordering dominos for %s to %s\n", order.ServiceMethod, strings.Replace(obj.AddressFmt(order.Address), "\n", " ", -1))
+	if t := order.FutureTime(); !t.IsZero() {
+		c.Printf("Scheduled for %s\n", t.Format("Mon Jan 2 3:04 PM"))
+	}
+	if order.Address.DeliveryInstructions != "" {
+		c.Printf("Delivery instructions: %s\n", order.Address.DeliveryInstructions)
+	}
+	if !order.Tip().IsZero() {
+		c.Printf("Tip: %s\n", order.Tip().Decimal())
+	}
+	if order.Vehicle != nil {
+		c.Printf("Car: %s %s\n", order.Vehicle.Color, order.Vehicle.Make)
+	}
+	c.Println()
 
 	if c.logonly {
-		log.Println("logging order:", dawg.OrderToJSON(order))
+		logger.Info("logging order", "order", order)
 		return nil
 	}
 
+	if err = c.checkDuplicate(order); err != nil {
+		return err
+	}
+	if err = c.checkBudget(order); err != nil {
+		return err
+	}
 	if !yesOrNo(os.Stdin, "Would you like to purchase this order? (y/n)") {
 		return nil
 	}
 
 	c.Printf("sending order '%s'...\n", order.Name())
-	// TODO: save the order id as a traced order and give it a timeout of
-	// an hour or two.
-	err = order.PlaceOrder()
-	// logging happens after so any data from placeorder is included
-	log.Println("sending order:", dawg.OrderToJSON(order))
-	if err != nil {
+	if err = c.send(order); err != nil {
 		return err
 	}
 	c.Printf("sent to %s %s\n", order.Address.LineOne(), order.Address.City())
+	c.Printf("order id: %s\n", order.OrderID)
 
 	if c.verbose {
 		if order.ServiceMethod == dawg.Delivery {
@@ -391,6 +457,264 @@ func (c *orderCmd) Run(cmd *cobra.Command, args []string) (err error) {
 	return nil
 }
 
+// newOrderSender creates an order command without a cobra command so that
+// other commands can send orders.
+func newOrderSender(b cli.Builder) *orderCmd {
+	c := &orderCmd{
+		conf:       b.Config(),
+		verbose:    false,
+		getaddress: b.Address,
+		price:      (*dawg.Order).Price,
+		store: func(id string) (*dawg.Store, error) {
+			return b.Config().GetMarket().NewStore(id, "", nil)
+		},
+	}
+	c.UserFinder = client.NewUserGetter(b, os.Stdin)
+	c.CardFinder = client.NewCardGetter(b, os.Stdin)
+	c.db = b.DB()
+	return c
+}
+
+// fill adds the payment, customer info, and address to an order.
+func (c *orderCmd) fill(order *dawg.Order) error {
+	order.SetMarket(c.conf.GetMarket())
+	if order.PointsRedeemed() > 0 {
+		// loyalty rewards can only be redeemed by the account that owns them
+		user, err := c.User()
+		if err != nil {
+			return err
+		}
+		order.SetUser(user)
+	}
+
+	card, err := c.payment()
+	if err != nil {
+		return err
+	}
+	order.AddCard(card)
+
+	names := strings.Split(c.conf.Name, " ")
+	if len(names) >= 1 {
+		order.FirstName = eitherOr(c.fname, names[0])
+	}
+	if len(names) >= 2 {
+		order.LastName = eitherOr(c.lname, strings.Join(names[1:], " "))
+	}
+	order.Email = eitherOr(c.email, c.conf.Email)
+	order.Phone = eitherOr(c.phone, c.conf.Phone)
+	order.Address = dawg.StreetAddrFromAddress(c.getaddress())
+	if c.instructions != "" {
+		if err = order.SetDeliveryInstructions(c.instructions); err != nil {
+			return err
+		}
+	}
+	tip, err := parseAmount("tip", c.tip, order.Market().Currency)
+	if err != nil {
+		return err
+	}
+	if !tip.IsZero() && order.ServiceMethod != dawg.Delivery {
+		return errors.New("a tip can only be given for delivery orders")
+	}
+	if err = c.checkService(order); err != nil {
+		return err
+	}
+	return order.SetTip(tip)
+}
+
+// parseAmount parses an amount of money given to a flag in a currency. An
+// empty amount is zero.
+func parseAmount(flag, s, currency string) (dawg.Money, error) {
+	if strings.TrimSpace(s) == "" {
+		return dawg.Money{}, nil
+	}
+	m, err := dawg.ParseMoney(s)
+	if err != nil {
+		return dawg.Money{}, fmt.Errorf("bad --%s '%s': %v", flag, strings.TrimSpace(s), err)
+	}
+	return m.In(currency), nil
+}
+
+// checkService makes sure that the order's store offers its service method
+// and adds the car for drive-up carryout orders.
+func (c *orderCmd) checkService(order *dawg.Order) error {
+	m := order.ServiceMethod
+	if err := m.Valid(); err != nil {
+		return err
+	}
+	if m != dawg.Delivery && m != dawg.Carryout {
+		store, err := c.store(order.StoreID)
+		if err != nil {
+			return err
+		}
+		if err = store.Supports(m); err != nil {
+			return err
+		}
+	}
+	if !m.NeedsVehicle() {
+		return nil
+	}
+	order.Vehicle = &dawg.Vehicle{
+		Make:  eitherOr(c.carMake, c.conf.Car.Make),
+		Color: eitherOr(c.carColor, c.conf.Car.Color),
+	}
+	if order.Vehicle.Make == "" || order.Vehicle.Color == "" {
+		return errors.New("drive-up carryout needs the make and color of your car (see --car-make and --car-color)")
+	}
+	return nil
+}
+
+// send places the order, keeping a record of the placement and adding the
+// order to the history if it goes through.
+func (c *orderCmd) send(order *dawg.Order) error {
+	// the placement is saved before sending so that there is a record of it
+	// even if the program does not get a response.
+	placement, err := data.NewPlacement(order)
+	if err != nil {
+		return err
+	}
+	if err = data.SavePlacement(c.db, placement); err != nil {
+		return err
+	}
+
+	err = order.PlaceOrder()
+	// logging happens after so any data from placeorder is included
+	logger.Info("sending order", "order", order, "placement", placement.ID)
+	placement.Update(order, err)
+	err = errs.Pair(err, data.SavePlacement(c.db, placement))
+	if placement.Status == data.PlacementPlaced {
+		err = errs.Pair(err, data.SavePlacedOrder(c.db, data.NewPlacedOrder(placement, order)))
+	}
+	return err
+}
+
+// schedule sets the order's future order time from the --at flag.
+func (c *orderCmd) schedule(o *dawg.Order) error {
+	if c.at == "" {
+		return nil
+	}
+	t, err := time.ParseInLocation(futureTimeFmt, c.at, time.Local)
+	if err != nil {
+		return fmt.Errorf("bad --at time: use the \"yyyy-mm-dd hh:mm\" format")
+	}
+	store, err := c.store(o.StoreID)
+	if err != nil {
+		return err
+	}
+	return o.SetFutureTime(store, t)
+}
+
+// checkDuplicate returns an error if the same order was sent recently.
+func (c *orderCmd) checkDuplicate(o *dawg.Order) error {
+	if c.force {
+		return nil
+	}
+	window, err := time.ParseDuration(eitherOr(c.conf.DuplicateWindow, "30m"))
+	if err != nil {
+		return fmt.Errorf("bad duplicate-window in config: %v", err)
+	}
+	prev, err := data.RecentPlacement(c.db, o, window)
+	if err != nil || prev == nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("this order was already sent %s ago (placement %s: %s)",
+		time.Since(prev.Time).Round(time.Second), prev.ID, prev.Status)
+	if prev.Status != data.PlacementPlaced && prev.OrderID != "" {
+		if ok, err := o.Market().OrderReceived(prev.Phone, prev.StoreID, prev.OrderID); err == nil {
+			if ok {
+				msg += "\nthe store has received it"
+			} else {
+				msg += "\nthe store has no record of it yet"
+			}
+		}
+	}
+	return fmt.Errorf("%s\nuse --force to send it again", msg)
+}
+
+// checkBudget returns an error if the order would go over the per order or
+// monthly budget set in the config.
+func (c *orderCmd) checkBudget(o *dawg.Order) error {
+	currency := o.Market().Currency
+	perOrder, err := parseBudget(c.conf.Budget.Order, currency)
+	if err != nil {
+		return err
+	}
+	monthly, err := parseBudget(c.conf.Budget.Monthly, currency)
+	if err != nil {
+		return err
+	}
+	if perOrder.IsZero() && monthly.IsZero() {
+		return nil
+	}
+	price, err := c.price(o)
+	if err != nil {
+		return err
+	}
+	price = price.Add(o.Tip())
+	c.Printf("Order total: %s\n", price.Decimal())
+	if c.overBudget {
+		return nil
+	}
+
+	if !perOrder.IsZero() && price.Cmp(perOrder) > 0 {
+		return fmt.Errorf("this order is over the %s per order budget by %s\nuse --over-budget to send it anyway",
+			perOrder.Decimal(), price.Sub(perOrder).Decimal())
+	}
+	if !monthly.IsZero() {
+		spent, err := monthlySpending(c.db, time.Now(), currency)
+		if err != nil {
+			return err
+		}
+		if total := spent.Add(price); total.Cmp(monthly) > 0 {
+			return fmt.Errorf("this order would go over the %s monthly budget by %s (%s spent this month)\nuse --over-budget to send it anyway",
+				monthly.Decimal(), total.Sub(monthly).Decimal(), spent.Decimal())
+		}
+	}
+	return nil
+}
+
+func (c *orderCmd) printPlacements() error {
+	placements, err := data.Placements(c.db)
+	if err != nil {
+		return err
+	}
+	if len(placements) == 0 {
+		c.Println("No orders have been sent.")
+		return nil
+	}
+	tw := newTabWriter(c.Output())
+	fmt.Fprintln(tw, "PLACEMENT\tTIME\tORDER\tSTORE\tSTATUS\tORDER ID\tPULSE GUID")
+	for _, p := range placements {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			p.ID, p.Time.Format("2006-01-02 15:04"), p.OrderName, p.StoreID,
+			p.Status, p.OrderID, p.PulseOrderGUID)
+	}
+	return tw.Flush()
+}
+
+// payment uses the card given with flags, falling back on the card stored in
+// the card vault.
+func (c *orderCmd) payment() (dawg.Card, error) {
+	number, expiration, zip := c.number, c.expiration, ""
+	if number == "" || expiration == "" {
+		stored, err := c.Card()
+		if err != nil {
+			return nil, err
+		}
+		number = eitherOr(number, stored.Number)
+		expiration = eitherOr(expiration, stored.Expiration)
+		zip = stored.BillingZip
+	}
+	card := dawg.NewCard(number, expiration, c.cvv)
+	if card == nil {
+		return nil, errors.New("bad card expiration date: use the mm/yy format")
+	}
+	if p, ok := card.(*dawg.Payment); ok {
+		p.PostalCode = zip
+	}
+	return card, nil
+}
+
 func eitherOr(s1, s2 string) string {
 	if len(s1) == 0 {
 		return s2
@@ -400,23 +724,45 @@ func eitherOr(s1, s2 string) string {
 
 // NewOrderCmd creates a new order command.
 func NewOrderCmd(b cli.Builder) cli.CliCommand {
-	c := &orderCmd{
-		verbose:    false,
-		getaddress: b.Address,
-	}
+	c := newOrderSender(b)
 	c.CliCommand = b.Build("order", "Send an order from the cart to dominos.", c)
-	c.db = b.DB()
 	c.Cmd().Long = `The order command is the final destination for an order. This is where
 the order will be populated with payment information and sent off to dominos.
 
 The --cvv flag must be specified, and the config file will never store the
 cvv. In addition to keeping the cvv safe, payment information will never be
-stored the program cache with orders.
+stored the program cache with orders. If no card is given with --number and
+--expiration, the card stored with 'apizza config card set' is used.
+
+Every time an order is sent it is recorded so that the same order is not sent
+twice by accident. An order that was sent within the last 'duplicate-window'
+(see 'apizza config') will not be sent again unless --force is given.
+
+Orders are made right away unless a time is given with --at, which uses the
+"yyyy-mm-dd hh:mm" format. The store must be open for the order's service
+method at that time.
+
+If 'budget.order' or 'budget.monthly' is set in the config, the order is
+priced first and will not be sent if it goes over either budget unless
+--over-budget is given. See 'apizza spend' for how much has been spent.
+
+Drive-up carryout and dine-in orders can only be sent to stores that offer
+them. Drive-up carryout orders need the make and color of the car, which are
+given with --car-make and --car-color or 'car.make' and 'car.color' in the
+config.
 `
 	c.Cmd().PreRunE = cartPreRun(c.db)
 
 	flags := c.Cmd().Flags()
 	flags.BoolVarP(&c.verbose, "verbose", "v", c.verbose, "output the order command verbosely")
+	flags.BoolVar(&c.force, "force", false, "send the order even if it was already sent recently")
+	flags.StringVar(&c.tip, "tip", "", "add a tip for the delivery driver")
+	flags.StringVar(&c.instructions, "instructions", "", "give instructions to the delivery driver (the address's instructions are used by default)")
+	flags.StringVar(&c.at, "at", "", "schedule the order for a time in the future (\"yyyy-mm-dd hh:mm\")")
+	flags.BoolVar(&c.overBudget, "over-budget", false, "send the order even if it goes over the budget")
+	flags.StringVar(&c.carMake, "car-make", "", "the make of your car for drive-up carryout")
+	flags.StringVar(&c.carColor, "car-color", "", "the color of your car for drive-up carryout")
+	flags.BoolVar(&c.placements, "placements", false, "list the orders that have been sent and their dominos order ids")
 
 	flags.StringVar(&c.phone, "phone", "", "Set the phone number that will be used for this order")
 	flags.StringVar(&c.email, "email", "", "Set the email that will be used for this order")
@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/harrybrwn/apizza/cmd/cli"
+	"github.com/harrybrwn/apizza/cmd/client"
 	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/dawg"
 	"github.com/harrybrwn/apizza/pkg/tests"
 )
 
@@ -179,6 +183,31 @@ func TestOrder(t *testing.T) {
 	cmd.cvv = 0
 }
 
+func TestAddOrderOffline(t *testing.T) {
+	tests.InitHelpers(t)
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	dawg.SetOffline(true)
+	defer dawg.SetOffline(false)
+
+	c := newAddOrderCmd(r).(*addOrderCmd)
+	tests.Check(client.SaveStore(r.DB(), dawg.Carryout, r.Address(), &dawg.Store{ID: "4336"}))
+	r.Conf.Market = "CA"
+	store, err := dawg.Canada.DecodeStore([]byte(`{"StoreID":"10001"}`), dawg.Carryout, r.Address())
+	tests.Check(err)
+	tests.Check(client.SaveStore(r.DB(), dawg.Carryout, r.Address(), store))
+
+	tests.Check(c.Run(c.Cmd(), []string{"offline"}))
+	if c.Store().Market() != dawg.Canada {
+		t.Error("the saved store should be in the market it was found in")
+	}
+	o, err := data.GetOrder("offline", r.DB())
+	tests.Check(err)
+	tests.StrEq(o.StoreID, "10001", "order should be from the saved store")
+	tests.StrEq(string(o.ServiceMethod), string(dawg.Carryout), "wrong service")
+	tests.StrEq(o.Address.LineOne(), r.Address().LineOne(), "order should have the saved address")
+}
+
 func TestEitherOr(t *testing.T) {
 	if eitherOr("one", "") != "one" {
 		t.Error("wrong result from 'eitherOr'")
@@ -190,3 +219,156 @@ func TestEitherOr(t *testing.T) {
 		t.Error("wrong result from 'eitherOr'")
 	}
 }
+
+func TestOrderDuplicateGuard(t *testing.T) {
+	tests.InitHelpers(t)
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	c := NewOrderCmd(r).(*orderCmd)
+
+	o := &dawg.Order{StoreID: "4336", ServiceMethod: dawg.Carryout}
+	o.SetName("dinner")
+	tests.Check(c.checkDuplicate(o))
+	p, err := data.NewPlacement(o)
+	tests.Check(err)
+	p.Update(o, nil)
+	tests.Check(data.SavePlacement(r.DB(), p))
+
+	tests.Exp(c.checkDuplicate(o), "should not send the same order twice")
+	c.force = true
+	tests.Check(c.checkDuplicate(o))
+	c.force = false
+	r.Conf.DuplicateWindow = "1ns"
+	tests.Check(c.checkDuplicate(o))
+	r.Conf.DuplicateWindow = "soon"
+	tests.Exp(c.checkDuplicate(o), "should fail on a bad window")
+	r.Conf.DuplicateWindow = ""
+
+	c.placements = true
+	tests.Check(c.Run(c.Cmd(), nil))
+	if !r.Contains(p.ID) || !r.Contains("dinner") || !r.Contains(data.PlacementPlaced) {
+		t.Errorf("placements should be listed:\n%s", r.Out.String())
+	}
+}
+
+func TestOrderBudget(t *testing.T) {
+	tests.InitHelpers(t)
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	c := NewOrderCmd(r).(*orderCmd)
+	var priced bool
+	c.price = func(*dawg.Order) (dawg.Money, error) { priced = true; return dawg.Cents(3000), nil }
+
+	o := &dawg.Order{StoreID: "4336", ServiceMethod: dawg.Carryout}
+	tests.Check(c.checkBudget(o))
+	if priced {
+		t.Error("should not price the order without a budget")
+	}
+
+	r.Conf.Budget.Order = "$25"
+	tests.Exp(c.checkBudget(o), "should be over the per order budget")
+	c.overBudget = true
+	tests.Check(c.checkBudget(o))
+	c.overBudget = false
+	r.Conf.Budget.Order = "40"
+	tests.Check(c.checkBudget(o))
+
+	r.Conf.Budget.Monthly = "50"
+	tests.Check(data.SavePlacedOrder(r.DB(), &data.PlacedOrder{
+		ID: "aaaa", Time: time.Now(), Amounts: map[string]dawg.Money{"Customer": dawg.Cents(2500)}}))
+	tests.Exp(c.checkBudget(o), "should be over the monthly budget")
+	r.Conf.Budget.Monthly = "a lot"
+	tests.Exp(c.checkBudget(o), "should fail on a bad budget")
+	r.Conf.Budget.Order, r.Conf.Budget.Monthly = "", ""
+}
+
+func TestOrderSchedule(t *testing.T) {
+	tests.InitHelpers(t)
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	c := NewOrderCmd(r).(*orderCmd)
+	c.store = func(id string) (*dawg.Store, error) {
+		hours := dawg.StoreHours{}
+		hours.Sun = []struct {
+			OpenTime  string
+			CloseTime string
+		}{{OpenTime: "10:00", CloseTime: "22:00"}}
+		hours.Mon, hours.Tue, hours.Wed, hours.Thu, hours.Fri, hours.Sat =
+			hours.Sun, hours.Sun, hours.Sun, hours.Sun, hours.Sun, hours.Sun
+		return &dawg.Store{ID: id, Hours: hours}, nil
+	}
+
+	o := &dawg.Order{StoreID: "4336", ServiceMethod: dawg.Carryout}
+	tests.Check(c.schedule(o))
+	tests.StrEq(o.FutureOrderTime, "", "should not be scheduled without --at")
+
+	day := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	c.at = day + " 12:15"
+	tests.Check(c.schedule(o))
+	tests.StrEq(o.FutureOrderTime, day+" 12:15:00", "wrong future order time")
+	c.at = day + " 23:30"
+	tests.Exp(c.schedule(o), "the store is closed")
+	c.at = "tomorrow at noon"
+	tests.Exp(c.schedule(o), "should not take a bad time")
+}
+
+func TestOrderTip(t *testing.T) {
+	tests.InitHelpers(t)
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	c := NewOrderCmd(r).(*orderCmd)
+	c.number, c.expiration, c.cvv = "4111111111111111", "01/30", 123
+	c.tip, c.instructions = "3.5", "ring the bell"
+
+	o := &dawg.Order{StoreID: "4336", ServiceMethod: dawg.Delivery}
+	tests.Check(c.fill(o))
+	if o.Tip() != dawg.Cents(350) {
+		t.Errorf("wrong tip: %v", o.Tip())
+	}
+	tests.StrEq(o.Address.DeliveryInstructions, "ring the bell", "wrong delivery instructions")
+
+	o = &dawg.Order{StoreID: "4336", ServiceMethod: dawg.Carryout}
+	tests.Exp(c.fill(o), "carryout orders should not have a tip")
+	c.tip = "-1"
+	o.ServiceMethod = dawg.Delivery
+	tests.Exp(c.fill(o), "tips cannot be negative")
+	c.tip = "a lot"
+	tests.Exp(c.fill(o), "should not take a bad tip")
+	c.tip = "0.29"
+	tests.Check(c.fill(o))
+	if o.Tip().Units != 29 || o.Tip().Currency != "USD" {
+		t.Errorf("wrong tip: %v %s", o.Tip(), o.Tip().Currency)
+	}
+}
+
+func TestOrderService(t *testing.T) {
+	tests.InitHelpers(t)
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	c := NewOrderCmd(r).(*orderCmd)
+	c.number, c.expiration, c.cvv = "4111111111111111", "01/30", 123
+	c.store = func(id string) (*dawg.Store, error) {
+		return &dawg.Store{ID: id, ServiceIsOpen: map[string]bool{"DriveUpCarryout": true}}, nil
+	}
+
+	o := &dawg.Order{StoreID: "4336", ServiceMethod: dawg.DriveUpCarryout}
+	tests.Exp(c.fill(o), "drive-up carryout needs a car")
+	r.Conf.Car.Make = "Honda"
+	c.carColor = "blue"
+	tests.Check(c.fill(o))
+	if o.Vehicle == nil {
+		t.Fatal("no vehicle on drive-up order")
+	}
+	tests.StrEq(o.Vehicle.Make, "Honda", "car make should come from the config")
+	tests.StrEq(o.Vehicle.Color, "blue", "car color should come from the flag")
+
+	o = &dawg.Order{StoreID: "4336", ServiceMethod: dawg.DineIn}
+	tests.Exp(c.fill(o), "the store does not offer dine-in")
+
+	r.Conf.Market = "CA"
+	o = &dawg.Order{StoreID: "4336", ServiceMethod: dawg.Carryout}
+	tests.Check(c.fill(o))
+	if o.Market() != dawg.Canada {
+		t.Error("orders should be sent to the market in the config")
+	}
+}
@@ -1,9 +1,10 @@
 package cli
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/harrybrwn/apizza/cmd/internal/obj"
+	"github.com/harrybrwn/apizza/dawg"
 	"github.com/harrybrwn/apizza/pkg/config"
 )
 
@@ -18,7 +19,28 @@ type Config struct {
 		Number     string `config:"number" json:"number"`
 		Expiration string `config:"expiration" json:"expiration"`
 	} `config:"card" json:"card"`
-	Service string `config:"service" default:"Delivery" json:"service"`
+	Service dawg.ServiceMethod `config:"service" default:"Delivery" json:"service"`
+
+	// Market is the country code of the dominos market to order from, like
+	// "US" or "CA".
+	Market string `config:"market" default:"US" json:"market"`
+
+	// Car is the customer's car for drive-up carryout orders.
+	Car struct {
+		Make  string `config:"make" json:"make"`
+		Color string `config:"color" json:"color"`
+	} `config:"car" json:"car"`
+
+	// DuplicateWindow is how long after sending an order that the same order
+	// cannot be sent again without --force.
+	DuplicateWindow string `config:"duplicate-window" default:"30m" json:"duplicate-window"`
+
+	// Budget is the most that can be spent on one order and in one month
+	// without --over-budget. An empty budget has no limit.
+	Budget struct {
+		Order   string `config:"order" json:"order"`
+		Monthly string `config:"monthly" json:"monthly"`
+	} `config:"budget" json:"budget"`
 }
 
 // Get a config variable
@@ -29,9 +51,27 @@ func (c *Config) Get(key string) interface{} {
 // Set a config variable
 func (c *Config) Set(key string, val interface{}) error {
 	if config.FieldName(c, key) == "Service" {
-		if val != "Delivery" && val != "Carryout" {
-			return errors.New("service must be either 'Delivery' or 'Carryout'")
+		m, err := dawg.ParseServiceMethod(fmt.Sprint(val))
+		if err != nil {
+			return err
 		}
+		val = string(m)
+	}
+	if config.FieldName(c, key) == "Market" {
+		m, err := dawg.GetMarket(fmt.Sprint(val))
+		if err != nil {
+			return err
+		}
+		val = m.Code
 	}
 	return config.SetField(c, key, val)
 }
+
+// GetMarket returns the dominos market from the config. The UnitedStates is
+// used if the config has no market or an unknown one.
+func (c *Config) GetMarket() *dawg.Market {
+	if m, err := dawg.GetMarket(c.Market); err == nil {
+		return m
+	}
+	return dawg.UnitedStates
+}
@@ -0,0 +1,120 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/harrybrwn/apizza/cmd/cli"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/pkg/cache"
+	"github.com/harrybrwn/apizza/pkg/errs"
+)
+
+// PassphraseEnv is the environment variable that is checked for the card
+// vault passphrase before prompting for one.
+const PassphraseEnv = "APIZZA_CARD_PASSPHRASE"
+
+// CardFinder is a mixin that gives access to the card stored in the
+// encrypted card vault.
+type CardFinder interface {
+	// Card will decrypt and return the stored card.
+	Card() (*data.Card, error)
+	// SetCard will encrypt and store a card, replacing the old one.
+	SetCard(*data.Card) error
+}
+
+type cardgetter struct {
+	db   *cache.DataBase
+	conf *cli.Config
+	in   io.Reader
+	pass []byte
+}
+
+// NewCardGetter creates a CardFinder that uses the builder's database as the
+// card vault. The vault passphrase is read from $APIZZA_CARD_PASSPHRASE or
+// prompted for from the io.Reader given (see Prompt).
+//
+// Any card number left in plaintext in the config file is moved into the
+// vault the first time the vault is opened.
+func NewCardGetter(builder cli.Builder, in io.Reader) CardFinder {
+	return &cardgetter{
+		db:   builder.DB(),
+		conf: builder.Config(),
+		in:   in,
+	}
+}
+
+func (c *cardgetter) Card() (*data.Card, error) {
+	if !data.HasCard(c.db) && c.conf.Card.Number == "" {
+		return nil, data.ErrNoCard
+	}
+	pass, err := c.passphrase()
+	if err != nil {
+		return nil, err
+	}
+	if err = c.migrate(pass); err != nil {
+		return nil, err
+	}
+	return data.LoadCard(c.db, pass)
+}
+
+func (c *cardgetter) SetCard(card *data.Card) error {
+	pass, err := c.passphrase()
+	if err != nil {
+		return err
+	}
+	if err = data.SaveCard(c.db, card, pass); err != nil {
+		return err
+	}
+	c.clearConfig()
+	return nil
+}
+
+// migrate moves a plaintext card from the config file into the vault.
+func (c *cardgetter) migrate(pass []byte) error {
+	if c.conf.Card.Number == "" {
+		return nil
+	}
+	if data.HasCard(c.db) {
+		// make sure the passphrase is right before replacing the old card
+		if _, err := data.LoadCard(c.db, pass); err != nil {
+			return err
+		}
+	}
+	card := &data.Card{
+		Number:     c.conf.Card.Number,
+		Expiration: c.conf.Card.Expiration,
+		BillingZip: c.conf.Address.Zip(),
+	}
+	if err := data.SaveCard(c.db, card, pass); err != nil {
+		return err
+	}
+	c.clearConfig()
+	fmt.Fprintln(os.Stderr, "Moved the card in the config file to the encrypted card vault.")
+	return nil
+}
+
+func (c *cardgetter) clearConfig() {
+	c.conf.Card.Number = ""
+	c.conf.Card.Expiration = ""
+}
+
+func (c *cardgetter) passphrase() ([]byte, error) {
+	if c.pass != nil {
+		return c.pass, nil
+	}
+	if pass := os.Getenv(PassphraseEnv); pass != "" {
+		c.pass = []byte(pass)
+		return c.pass, nil
+	}
+	line, err := Prompt(c.in).Secret("Card vault passphrase: ")
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if line == "" {
+		return nil, errs.New("no passphrase given")
+	}
+	c.pass = []byte(line)
+	return c.pass, nil
+}
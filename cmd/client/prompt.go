@@ -0,0 +1,62 @@
+package client
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// Prompter reads the answers to prompts from an input.
+type Prompter struct {
+	in  io.Reader
+	buf *bufio.Reader
+	out io.Writer
+}
+
+var (
+	promptMu  sync.Mutex
+	prompters = map[io.Reader]*Prompter{}
+)
+
+// Prompt returns the Prompter for an input. Every prompt that reads from the
+// same input shares one Prompter so that input buffered by one prompt is not
+// lost to the next one.
+func Prompt(in io.Reader) *Prompter {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	p, ok := prompters[in]
+	if !ok {
+		p = &Prompter{in: in, buf: bufio.NewReader(in), out: os.Stderr}
+		prompters[in] = p
+	}
+	return p
+}
+
+// Line prints the message and reads one line of input without the line
+// ending.
+func (p *Prompter) Line(msg string) (string, error) {
+	fmt.Fprint(p.out, msg)
+	line, err := p.buf.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.Trim(line, "\r\n"), nil
+}
+
+// Secret prints the message and reads one line of input. When the input is
+// a terminal, what is typed is not shown.
+func (p *Prompter) Secret(msg string) (string, error) {
+	f, ok := p.in.(*os.File)
+	if !ok || p.buf.Buffered() > 0 || !term.IsTerminal(int(f.Fd())) {
+		return p.Line(msg)
+	}
+	fmt.Fprint(p.out, msg)
+	b, err := term.ReadPassword(int(f.Fd()))
+	fmt.Fprintln(p.out)
+	return string(b), err
+}
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestPrompt(t *testing.T) {
+	tests.InitHelpers(t)
+	in := strings.NewReader("hunter2\r\nvault pass\ny")
+	p := Prompt(in)
+	p.out = ioutil.Discard
+	if Prompt(in) != p {
+		t.Fatal("prompts from the same input should share a prompter")
+	}
+
+	// the card getter and the user getter read from the same input
+	line, err := Prompt(in).Secret("Password: ")
+	tests.Check(err)
+	tests.StrEq(line, "hunter2", "wrong password")
+	line, err = Prompt(in).Secret("Passphrase: ")
+	tests.Check(err)
+	tests.StrEq(line, "vault pass", "input should not be lost between prompts")
+	line, err = Prompt(in).Line("(y/n) ")
+	tests.Check(err)
+	tests.StrEq(line, "y", "the last line does not need a line ending")
+	_, err = Prompt(in).Line("")
+	tests.Exp(err, "there is no more input")
+
+	var out bytes.Buffer
+	p = Prompt(strings.NewReader("x\n"))
+	p.out = &out
+	_, err = p.Secret("Password: ")
+	tests.Check(err)
+	tests.StrEq(out.String(), "Password: ", "the prompt should be printed")
+}
@@ -1,9 +1,14 @@
 package client
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
 	"github.com/harrybrwn/apizza/cmd/cli"
 	"github.com/harrybrwn/apizza/cmd/internal/obj"
 	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
 	"github.com/harrybrwn/apizza/pkg/errs"
 )
 
@@ -21,26 +26,41 @@ type StoreFinder interface {
 // get a store.
 type storegetter struct {
 	getaddr   func() dawg.Address
-	getmethod func() string
+	getmethod func() dawg.ServiceMethod
+	getmarket func() *dawg.Market
+	getdb     func() *cache.DataBase
 	dstore    *dawg.Store
 }
 
 // NewStoreGetter will create a new storefinder.
 func NewStoreGetter(builder cli.Builder) StoreFinder {
 	return &storegetter{
-		getmethod: func() string {
+		getmethod: func() dawg.ServiceMethod {
 			return builder.Config().Service
 		},
+		getmarket: func() *dawg.Market {
+			return builder.Config().GetMarket()
+		},
 		getaddr: builder.Address,
+		getdb:   builder.DB,
 		dstore:  nil,
 	}
 }
 
-// NewStoreGetterFunc creates a new store getter from two funcs
-func NewStoreGetterFunc(service func() string, addr func() dawg.Address) StoreFinder {
+// NewStoreGetterFunc creates a new store getter from funcs for the database,
+// market, service, and address. The database is used to save the stores that
+// are found so they can be used in offline mode, and can be nil.
+func NewStoreGetterFunc(
+	db func() *cache.DataBase,
+	market func() *dawg.Market,
+	service func() dawg.ServiceMethod,
+	addr func() dawg.Address,
+) StoreFinder {
 	return &storegetter{
+		getmarket: market,
 		getmethod: service,
 		getaddr:   addr,
+		getdb:     db,
 		dstore:    nil,
 	}
 }
@@ -52,7 +72,15 @@ func (s *storegetter) Store() *dawg.Store {
 		if obj.AddrIsEmpty(address) {
 			errs.Handle(errs.New("no address given in config file or as flag"), "Error", 1)
 		}
-		s.dstore, err = dawg.NearestStore(address, s.getmethod())
+		key := storeKey(s.getmarket(), s.getmethod(), address)
+		if dawg.IsOffline() {
+			s.dstore, err = s.savedStore(key)
+		} else {
+			s.dstore, err = s.getmarket().NearestStore(address, s.getmethod())
+			if err == nil {
+				err = s.saveStore(key, s.dstore)
+			}
+		}
 		if err != nil {
 			errs.Handle(err, "Store Find Error", 1) // will exit
 		}
@@ -60,6 +88,89 @@ func (s *storegetter) Store() *dawg.Store {
 	return s.dstore
 }
 
+// storeKey is the database key for the store found for an address.
+func storeKey(m *dawg.Market, service dawg.ServiceMethod, addr dawg.Address) string {
+	return strings.ToLower(fmt.Sprintf("store/%s/%s/%s, %s, %s %s", m.Code, service,
+		addr.LineOne(), addr.City(), addr.StateCode(), addr.Zip()))
+}
+
+func (s *storegetter) database() *cache.DataBase {
+	if s.getdb == nil {
+		return nil
+	}
+	return s.getdb()
+}
+
+// storeRecord is what is saved in the database for a store. The store data
+// does not include the market, service, and address that orders from the
+// store need, so they are saved with it.
+type storeRecord struct {
+	Market  string
+	Service dawg.ServiceMethod
+	Address *obj.Address
+	Store   json.RawMessage
+}
+
+func (s *storegetter) saveStore(key string, store *dawg.Store) error {
+	db := s.database()
+	if db == nil {
+		return nil
+	}
+	data, err := json.Marshal(store)
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(&storeRecord{
+		Market:  store.Market().Code,
+		Service: s.getmethod(),
+		Address: obj.FromAddress(s.getaddr()),
+		Store:   data,
+	})
+	if err != nil {
+		return err
+	}
+	return db.Put(key, raw)
+}
+
+// SaveStore saves the store found for an address so that it can be used in
+// offline mode.
+func SaveStore(db *cache.DataBase, service dawg.ServiceMethod, addr dawg.Address, store *dawg.Store) error {
+	sg := &storegetter{
+		getdb:     func() *cache.DataBase { return db },
+		getmethod: func() dawg.ServiceMethod { return service },
+		getaddr:   func() dawg.Address { return addr },
+	}
+	return sg.saveStore(storeKey(store.Market(), service, addr), store)
+}
+
+// savedStore gets the store that was last found for an address.
+func (s *storegetter) savedStore(key string) (*dawg.Store, error) {
+	var raw []byte
+	var err error
+	if db := s.database(); db != nil {
+		raw, err = db.Get(key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, errs.New("no store has been found for this address yet; run apizza without --offline to find one")
+	}
+	saved := storeRecord{}
+	if err = json.Unmarshal(raw, &saved); err != nil {
+		return nil, err
+	}
+	if saved.Store == nil {
+		// saved by an older version with only the store data
+		return s.getmarket().DecodeStore(raw, s.getmethod(), s.getaddr())
+	}
+	market, err := dawg.GetMarket(saved.Market)
+	if err != nil {
+		return nil, err
+	}
+	return market.DecodeStore(saved.Store, saved.Service, saved.Address)
+}
+
 func (s *storegetter) Address() dawg.Address {
 	return s.getaddr()
 }
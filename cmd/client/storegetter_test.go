@@ -0,0 +1,50 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestStoreGetterOffline(t *testing.T) {
+	tests.InitHelpers(t)
+	db := cmdtest.TempDB()
+	defer db.Destroy()
+	dawg.SetOffline(true)
+	defer dawg.SetOffline(false)
+
+	addr := &dawg.StreetAddr{Street: "1600 Pennsylvania Ave.", CityName: "Washington", State: "DC", Zipcode: "20500"}
+	sg := NewStoreGetterFunc(
+		func() *cache.DataBase { return db },
+		func() *dawg.Market { return dawg.UnitedStates },
+		func() dawg.ServiceMethod { return dawg.Carryout },
+		func() dawg.Address { return addr },
+	).(*storegetter)
+
+	key := storeKey(dawg.UnitedStates, dawg.Carryout, addr)
+	_, err := sg.savedStore(key)
+	tests.Exp(err, "there should be no saved store")
+
+	tests.Check(sg.saveStore(key, &dawg.Store{ID: "4336", Phone: "555-555-5555"}))
+	store := sg.Store()
+	tests.StrEq(store.ID, "4336", "wrong store id")
+	tests.StrEq(store.Phone, "555-555-5555", "wrong phone number")
+
+	if storeKey(dawg.UnitedStates, dawg.Delivery, addr) == key {
+		t.Error("stores should be saved for each service method")
+	}
+	// stores saved by older versions only have the store data
+	tests.Check(db.Put(key, []byte(`{"StoreID":"4337"}`)))
+	old, err := sg.savedStore(key)
+	tests.Check(err)
+	tests.StrEq(old.ID, "4337", "wrong store id")
+	tests.StrEq(old.NewOrder().Address.City(), "Washington", "old stores should use the config address")
+
+	sg.getdb = nil
+	tests.Check(sg.saveStore(key, store))
+	_, err = sg.savedStore(key)
+	tests.Exp(err, "should not find a store without a database")
+}
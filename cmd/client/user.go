@@ -0,0 +1,82 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/harrybrwn/apizza/cmd/cli"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/errs"
+)
+
+// PasswordEnv is the environment variable that is checked for the dominos
+// account password before prompting for one.
+const PasswordEnv = "APIZZA_PASSWORD"
+
+// UserFinder is a mixin that signs in to a dominos account and caches the
+// user's profile.
+type UserFinder interface {
+	// User will return the signed in dominos user.
+	User() (*dawg.UserProfile, error)
+}
+
+type usergetter struct {
+	getname    func() string
+	getservice func() dawg.ServiceMethod
+	getmarket  func() *dawg.Market
+	in         io.Reader
+	user       *dawg.UserProfile
+}
+
+// NewUserGetter creates a UserFinder that uses the email from the config as
+// the account username. The password is read from $APIZZA_PASSWORD or
+// prompted for from the io.Reader given (see Prompt).
+func NewUserGetter(builder cli.Builder, in io.Reader) UserFinder {
+	return &usergetter{
+		getname:    func() string { return builder.Config().Email },
+		getservice: func() dawg.ServiceMethod { return builder.Config().Service },
+		getmarket:  func() *dawg.Market { return builder.Config().GetMarket() },
+		in:         in,
+		user:       nil,
+	}
+}
+
+func (u *usergetter) User() (*dawg.UserProfile, error) {
+	if u.user != nil {
+		return u.user, nil
+	}
+	username := u.getname()
+	if username == "" {
+		return nil, errs.New("no account email (see 'apizza config set email=<email>')")
+	}
+	password, err := u.password(username)
+	if err != nil {
+		return nil, err
+	}
+	user, err := u.getmarket().SignIn(username, password)
+	if err != nil {
+		return nil, err
+	}
+	if service := u.getservice(); service != "" {
+		if err = user.SetServiceMethod(service); err != nil {
+			return nil, err
+		}
+	}
+	u.user = user
+	return u.user, nil
+}
+
+func (u *usergetter) password(username string) (string, error) {
+	if pw := os.Getenv(PasswordEnv); pw != "" {
+		return pw, nil
+	}
+	line, err := Prompt(u.in).Secret(fmt.Sprintf("Password for %s: ", username))
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	if line == "" {
+		return "", errs.New("no password given")
+	}
+	return line, nil
+}
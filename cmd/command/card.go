@@ -0,0 +1,104 @@
+package command
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/harrybrwn/apizza/cmd/cli"
+	"github.com/harrybrwn/apizza/cmd/client"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
+)
+
+// `apizza config card`
+type cardCmd struct {
+	cli.CliCommand
+	client.CardFinder
+	db   *cache.DataBase
+	conf *cli.Config
+	in   io.Reader
+
+	expiration string
+	zip        string
+}
+
+func newCardCmd(b cli.Builder, in io.Reader) cli.CliCommand {
+	c := &cardCmd{
+		CardFinder: client.NewCardGetter(b, in),
+		db:         b.DB(),
+		conf:       b.Config(),
+		in:         in,
+	}
+	c.CliCommand = b.Build("card", "Manage the card stored in the encrypted card vault", c)
+	c.SetOutput(b.Output())
+	c.Cmd().Long = `The card command manages the payment card that is stored encrypted in
+the apizza database. The vault passphrase is read from $` + client.PassphraseEnv + `
+or prompted for. The card's security code is never stored.`
+
+	set := b.Build("set", "Encrypt and store a card", cli.RunFunction(c.set))
+	set.Cmd().Long = `Encrypt and store a card. The card number is prompted for, without
+showing it, so that it does not end up in the shell history.`
+	set.Flags().StringVar(&c.expiration, "expiration", "", "the card's expiration date (mm/yy)")
+	set.Flags().StringVar(&c.zip, "zip", "", "the card's billing zip code")
+
+	c.Addcmd(
+		set,
+		b.Build("show", "Show the stored card with the number masked", cli.RunFunction(c.show)),
+		b.Build("clear", "Delete the stored card", cli.RunFunction(c.clear)),
+	)
+	return c
+}
+
+func (c *cardCmd) Run(cmd *cobra.Command, args []string) error {
+	return cmd.Usage()
+}
+
+func (c *cardCmd) set(cmd *cobra.Command, args []string) error {
+	if c.expiration == "" {
+		return errors.New("the card --expiration is needed")
+	}
+	if _, err := time.Parse("1/06", c.expiration); err != nil {
+		return errors.New("bad expiration date: use the mm/yy format")
+	}
+	number, err := client.Prompt(c.in).Secret("Card number: ")
+	if err != nil {
+		return err
+	}
+	number = strings.Join(strings.Fields(number), "")
+	if number == "" {
+		return errors.New("no card number given")
+	}
+	zip := c.zip
+	if zip == "" {
+		zip = c.conf.Address.Zip()
+	}
+	return c.SetCard(&data.Card{
+		Number:     number,
+		Expiration: c.expiration,
+		BillingZip: zip,
+	})
+}
+
+func (c *cardCmd) show(cmd *cobra.Command, args []string) error {
+	card, err := c.Card()
+	if err != nil {
+		return err
+	}
+	c.Printf("number:      %s\n", dawg.MaskCardNumber(card.Number))
+	c.Printf("expiration:  %s\n", card.Expiration)
+	if card.BillingZip != "" {
+		c.Printf("billing zip: %s\n", card.BillingZip)
+	}
+	return nil
+}
+
+func (c *cardCmd) clear(cmd *cobra.Command, args []string) error {
+	c.conf.Card.Number = ""
+	c.conf.Card.Expiration = ""
+	return data.DeleteCard(c.db)
+}
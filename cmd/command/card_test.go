@@ -0,0 +1,55 @@
+package command
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/harrybrwn/apizza/cmd/client"
+	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestCardCmd(t *testing.T) {
+	tests.InitHelpers(t)
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	c := newCardCmd(r, strings.NewReader("")).(*cardCmd)
+	tests.Check(os.Setenv(client.PassphraseEnv, "testpass"))
+	defer os.Unsetenv(client.PassphraseEnv)
+
+	if err := c.show(c.Cmd(), nil); err != data.ErrNoCard {
+		t.Errorf("expected ErrNoCard; got %v", err)
+	}
+
+	// plaintext cards in the config should be moved into the vault
+	r.Conf.Card.Number = "4111111111111111"
+	r.Conf.Card.Expiration = "01/30"
+	tests.Check(c.show(c.Cmd(), nil))
+	r.Compare(t, "number:      ************1111\nexpiration:  01/30\nbilling zip: 20500\n")
+	r.ClearBuf()
+	tests.StrEq(r.Conf.Card.Number, "", "card number should be removed from the config")
+	tests.StrEq(r.Conf.Card.Expiration, "", "expiration should be removed from the config")
+	if !data.HasCard(r.DB()) {
+		t.Fatal("card should be moved to the vault")
+	}
+
+	tests.Exp(c.set(c.Cmd(), nil), "should need an expiration")
+	c.expiration, c.zip = "13-30", "10001"
+	tests.Exp(c.set(c.Cmd(), nil), "should not take a bad expiration")
+	c.expiration = "12/31"
+	tests.Exp(c.set(c.Cmd(), nil), "should need a card number")
+	c.in = strings.NewReader("\n")
+	tests.Exp(c.set(c.Cmd(), nil), "should not take an empty card number")
+	c.in = strings.NewReader("5555 5555 5555 4444\n")
+	tests.Check(c.set(c.Cmd(), nil))
+	tests.Check(c.show(c.Cmd(), nil))
+	r.Compare(t, "number:      ************4444\nexpiration:  12/31\nbilling zip: 10001\n")
+
+	tests.Check(c.clear(c.Cmd(), nil))
+	if data.HasCard(r.DB()) {
+		t.Error("card should be deleted")
+	}
+	tests.Exp(set([]string{"card.number=4111111111111111"}), "should not set the card in the config")
+}
@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -40,9 +41,6 @@ type configCmd struct {
 	edit   bool
 
 	setDefaultAddress string
-
-	card string
-	exp  string
 }
 
 func (c *configCmd) Run(cmd *cobra.Command, args []string) error {
@@ -104,11 +102,8 @@ ex. 'apizza config get name' or 'apizza config set name=<your name>'`
 	c.Flags().BoolVarP(&c.edit, "edit", "e", false, "open the config file with the text editor set by $EDITOR")
 	c.Flags().StringVar(&c.setDefaultAddress, "set-address", "", "name of a pre-stored address (see 'apizza address --new')")
 
-	// c.Flags().StringVar(&c.card, "card", "", "store encrypted credit card number in the database")
-	// c.Flags().StringVar(&c.exp, "expiration", "", "store the encrypted expiration data of your credit card")
-
 	cmd := c.Cmd()
-	cmd.AddCommand(configSetCmd, configGetCmd)
+	cmd.AddCommand(configSetCmd, configGetCmd, newCardCmd(b, os.Stdin).Cmd())
 	return c
 }
 
@@ -143,6 +138,9 @@ func set(args []string) error {
 			return errors.New(`use '<key>=<value>' format (no spaces), use <key>='-' to set as empty`)
 		}
 
+		if strings.HasPrefix(strings.ToLower(keys[0]), "card") {
+			return errors.New("card details are not stored in the config file, use 'apizza config card set'")
+		}
 		if keys[1] == "-" {
 			keys[1] = ""
 		}
@@ -9,6 +9,7 @@ import (
 	"github.com/harrybrwn/apizza/cmd/cli"
 	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
 	"github.com/harrybrwn/apizza/cmd/internal/obj"
+	"github.com/harrybrwn/apizza/dawg"
 	"github.com/harrybrwn/apizza/pkg/config"
 	"github.com/harrybrwn/apizza/pkg/errs"
 	"github.com/harrybrwn/apizza/pkg/tests"
@@ -35,11 +36,22 @@ address:
   cityname: "Washington DC"
   state: ""
   zipcode: "20500"
+  unittype: ""
+  unitnumber: ""
+  instructions: ""
 default-address: ""
 card:
   number: ""
   expiration: ""
 service: "Carryout"
+market: "US"
+car:
+  make: ""
+  color: ""
+duplicate-window: ""
+budget:
+  order: ""
+  monthly: ""
 `
 
 func TestConfigStruct(t *testing.T) {
@@ -53,6 +65,19 @@ func TestConfigStruct(t *testing.T) {
 	tests.Check(r.Config().Set("name", "not joe"))
 	tests.StrEq(r.Config().Get("Name").(string), "not joe", "wrong value from Config.Get")
 	tests.Check(r.Config().Set("name", "joe"))
+
+	tests.Check(r.Config().Set("service", "curbside"))
+	tests.StrEq(r.Config().Get("service").(string), "DriveUpCarryout", "service aliases should be normalized")
+	tests.Exp(r.Config().Set("service", "teleport"), "expected an error for a bad service")
+	tests.Check(r.Config().Set("car.make", "Honda"))
+	tests.StrEq(r.Config().Car.Make, "Honda", "could not set the car's make")
+
+	tests.Check(r.Config().Set("market", "canada"))
+	tests.StrEq(r.Config().Get("market").(string), "CA", "market names should be set as the country code")
+	if r.Config().GetMarket() != dawg.Canada {
+		t.Error("wrong market from the config")
+	}
+	tests.Exp(r.Config().Set("market", "atlantis"), "expected an error for an unknown market")
 }
 
 func TestConfigCmd(t *testing.T) {
@@ -106,14 +131,27 @@ func TestConfigEdit(t *testing.T) {
         "Street": "",
         "CityName": "",
         "State": "",
-        "Zipcode": ""
+        "Zipcode": "",
+        "UnitType": "",
+        "UnitNumber": "",
+        "DeliveryInstructions": ""
     },
     "DefaultAddress": "",
     "Card": {
         "Number": "",
         "Expiration": ""
     },
-    "Service": "Delivery"
+    "Service": "Delivery",
+    "Market": "US",
+    "Car": {
+        "Make": "",
+        "Color": ""
+    },
+    "DuplicateWindow": "30m",
+    "Budget": {
+        "Order": "",
+        "Monthly": ""
+    }
 }`
 	t.Run("edit output", func(t *testing.T) {
 		if os.Getenv("TRAVIS") == "true" {
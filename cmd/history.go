@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/harrybrwn/apizza/cmd/cli"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/cmd/internal/obj"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
+)
+
+const historyDateFmt = "2006-01-02"
+
+// `apizza history`
+type historyCmd struct {
+	cli.CliCommand
+	db   *cache.DataBase
+	conf *cli.Config
+
+	since string
+	until string
+	store string
+	name  string
+}
+
+// NewHistoryCmd creates the 'history' command.
+func NewHistoryCmd(b cli.Builder) cli.CliCommand {
+	c := &historyCmd{db: b.DB(), conf: b.Config()}
+	c.CliCommand = b.Build("history", "Show the orders that have been sent.", c)
+	c.Cmd().Long = `The history command shows the orders that have been sent to dominos
+with 'apizza order'.
+
+Orders are referred to by the id in the first column of 'apizza history list'.
+Like git commits, the first few characters of an id are enough.`
+
+	flags := c.Cmd().PersistentFlags()
+	flags.StringVar(&c.since, "since", "", "only show orders sent on or after this date (yyyy-mm-dd)")
+	flags.StringVar(&c.until, "until", "", "only show orders sent on or before this date (yyyy-mm-dd)")
+	flags.StringVar(&c.store, "store", "", "only show orders sent to this store id")
+
+	reorder := b.Build("reorder <id>", "Copy a sent order into a new cart", cli.RunFunction(c.reorder))
+	reorder.Flags().StringVarP(&c.name, "name", "n", "", "set the name of the new order")
+
+	c.Addcmd(
+		b.Build("list", "List the sent orders", cli.RunFunction(c.list)),
+		b.Build("show <id>", "Show the details of a sent order", cli.RunFunction(c.show)),
+		b.Build("stats", "Show stats about the sent orders", cli.RunFunction(c.stats)),
+		reorder,
+	)
+	return c
+}
+
+// Run will list the order history.
+func (c *historyCmd) Run(cmd *cobra.Command, args []string) error {
+	return c.list(cmd, args)
+}
+
+func (c *historyCmd) orders() ([]*data.PlacedOrder, error) {
+	f, err := historyFilter(c.since, c.until, c.store)
+	if err != nil {
+		return nil, err
+	}
+	return data.PlacedOrders(c.db, f)
+}
+
+// historyFilter creates a history filter from the values of the --since,
+// --until, and --store flags.
+func historyFilter(since, until, store string) (*data.HistoryFilter, error) {
+	f := &data.HistoryFilter{StoreID: store}
+	var err error
+	if since != "" {
+		if f.Since, err = time.ParseInLocation(historyDateFmt, since, time.Local); err != nil {
+			return nil, fmt.Errorf("bad --since date: %v", err)
+		}
+	}
+	if until != "" {
+		if f.Until, err = time.ParseInLocation(historyDateFmt, until, time.Local); err != nil {
+			return nil, fmt.Errorf("bad --until date: %v", err)
+		}
+		f.Until = f.Until.AddDate(0, 0, 1) // include the whole day
+	}
+	return f, nil
+}
+
+func (c *historyCmd) list(cmd *cobra.Command, args []string) error {
+	orders, err := c.orders()
+	if err != nil {
+		return err
+	}
+	return printHistory(c.Output(), orders)
+}
+
+func (c *historyCmd) show(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("give one order id")
+	}
+	po, err := data.GetPlacedOrder(c.db, args[0])
+	if err != nil {
+		return err
+	}
+	return printPlacedOrder(c.Output(), po)
+}
+
+func (c *historyCmd) stats(cmd *cobra.Command, args []string) error {
+	f, err := historyFilter(c.since, c.until, c.store)
+	if err != nil {
+		return err
+	}
+	// only orders in one currency can be added up
+	f.Currency = c.conf.GetMarket().Currency
+	orders, err := data.PlacedOrders(c.db, f)
+	if err != nil {
+		return err
+	}
+	return printHistoryStats(c.Output(), orders)
+}
+
+func (c *historyCmd) reorder(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("give one order id")
+	}
+	po, err := data.GetPlacedOrder(c.db, args[0])
+	if err != nil {
+		return err
+	}
+	name := c.name
+	if name == "" {
+		name = eitherOr(po.Name, "reorder") + "-again"
+	}
+	return data.SaveOrder(po.ToOrder(name), c.Output(), c.db)
+}
+
+func printHistory(w io.Writer, orders []*data.PlacedOrder) error {
+	if len(orders) == 0 {
+		_, err := fmt.Fprintln(w, "No orders have been sent.")
+		return err
+	}
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "ID\tDATE\tNAME\tSTORE\tMETHOD\tPRODUCTS\tTOTAL")
+	for _, po := range orders {
+		fmt.Fprintf(tw, "%.7s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			po.ID, po.Time.Format("2006-01-02 15:04"), po.Name, po.StoreID,
+			po.ServiceMethod, productList(po.Products), po.Total().Decimal())
+	}
+	return tw.Flush()
+}
+
+func printPlacedOrder(w io.Writer, po *data.PlacedOrder) error {
+	tw := newTabWriter(w)
+	fmt.Fprintf(tw, "ID:\t%s\n", po.ID)
+	fmt.Fprintf(tw, "Name:\t%s\n", po.Name)
+	fmt.Fprintf(tw, "Sent:\t%s\n", po.Time.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(tw, "Store:\t%s\n", po.StoreID)
+	fmt.Fprintf(tw, "Method:\t%s\n", po.ServiceMethod)
+	if po.Address != nil {
+		fmt.Fprintf(tw, "Address:\t%s\n", obj.AddressFmtIndent(po.Address, 10))
+	}
+	if po.OrderID != "" {
+		fmt.Fprintf(tw, "Order ID:\t%s\n", po.OrderID)
+	}
+	if po.PulseOrderGUID != "" {
+		fmt.Fprintf(tw, "Pulse GUID:\t%s\n", po.PulseOrderGUID)
+	}
+	if po.CardLastFour != "" {
+		fmt.Fprintf(tw, "Payment:\t%s ending in %s\n", eitherOr(po.PaymentType, "card"), po.CardLastFour)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, "Products:")
+	for _, p := range po.Products {
+		fmt.Fprintf(w, "  %s\n", strings.TrimSpace(fmt.Sprintf("%dx %s %s", p.Qty, p.Code, p.Name)))
+	}
+	if len(po.Amounts) == 0 && po.Tip.IsZero() {
+		return nil
+	}
+	fmt.Fprintln(w, "Amounts:")
+	keys := make([]string, 0, len(po.Amounts))
+	for k := range po.Amounts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	tw = newTabWriter(w)
+	for _, k := range keys {
+		fmt.Fprintf(tw, "  %s:\t%s\n", k, po.Amounts[k].Decimal())
+	}
+	if !po.Tip.IsZero() {
+		fmt.Fprintf(tw, "  Tip:\t%s\n", po.Tip.Decimal())
+	}
+	return tw.Flush()
+}
+
+func printHistoryStats(w io.Writer, orders []*data.PlacedOrder) error {
+	if len(orders) == 0 {
+		_, err := fmt.Fprintln(w, "No orders have been sent.")
+		return err
+	}
+	var total dawg.Money
+	products := map[string]int{}
+	stores := map[string]int{}
+	for _, po := range orders {
+		total = total.Add(po.Total())
+		stores[po.StoreID]++
+		for _, p := range po.Products {
+			products[p.Code] += p.Qty
+		}
+	}
+
+	tw := newTabWriter(w)
+	fmt.Fprintf(tw, "Orders:\t%d\n", len(orders))
+	fmt.Fprintf(tw, "Total spent:\t%s\n", total.Decimal())
+	fmt.Fprintf(tw, "Average order:\t%s\n", average(total, len(orders)).Decimal())
+	fmt.Fprintf(tw, "First order:\t%s\n", orders[len(orders)-1].Time.Format(historyDateFmt))
+	fmt.Fprintf(tw, "Last order:\t%s\n", orders[0].Time.Format(historyDateFmt))
+	fmt.Fprintf(tw, "Favorite store:\t%s\n", mostCommon(stores))
+	fmt.Fprintf(tw, "Favorite product:\t%s\n", mostCommon(products))
+	return tw.Flush()
+}
+
+// average divides a total by n, rounding to the nearest cent.
+func average(total dawg.Money, n int) dawg.Money {
+	units, half := total.Units, int64(n)/2
+	if units < 0 {
+		half = -half
+	}
+	total.Units = (units + half) / int64(n)
+	return total
+}
+
+// mostCommon returns the key with the highest count, breaking ties
+// alphabetically so the output is stable.
+func mostCommon(counts map[string]int) string {
+	var best string
+	for k, n := range counts {
+		if n > counts[best] || (n == counts[best] && k < best) {
+			best = k
+		}
+	}
+	return best
+}
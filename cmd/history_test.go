@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func testHistory(t *testing.T, r *cmdtest.Recorder) {
+	jan := time.Date(2026, time.January, 10, 18, 30, 0, 0, time.Local)
+	for _, po := range []*data.PlacedOrder{
+		{ID: "aaaa1111", Name: "dinner", StoreID: "4336", ServiceMethod: dawg.Delivery, Time: jan,
+			Products: []*dawg.OrderProduct{{ItemCommon: dawg.ItemCommon{Code: "14SCREEN"}, Qty: 2}},
+			Amounts:  map[string]dawg.Money{"Customer": dawg.Cents(2550), "Tax": dawg.Cents(150)}},
+		{ID: "bbbb2222", Name: "lunch", StoreID: "1111", ServiceMethod: dawg.Carryout, Time: jan.AddDate(0, 0, 7),
+			Products: []*dawg.OrderProduct{{ItemCommon: dawg.ItemCommon{Code: "2LCOKE"}, Qty: 1}},
+			Amounts:  map[string]dawg.Money{"Customer": dawg.Cents(450)}},
+	} {
+		tests.Check(data.SavePlacedOrder(r.DB(), po))
+	}
+}
+
+func TestHistoryCmd(t *testing.T) {
+	tests.InitHelpers(t)
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	c := NewHistoryCmd(r).(*historyCmd)
+
+	tests.Check(c.list(c.Cmd(), nil))
+	r.Compare(t, "No orders have been sent.\n")
+	r.ClearBuf()
+	testHistory(t, r)
+
+	tests.Check(c.list(c.Cmd(), nil))
+	r.Compare(t, `ID       DATE              NAME    STORE  METHOD    PRODUCTS     TOTAL
+bbbb222  2026-01-17 18:30  lunch   1111   Carryout  2LCOKE       4.50
+aaaa111  2026-01-10 18:30  dinner  4336   Delivery  2x 14SCREEN  25.50
+`)
+	r.ClearBuf()
+
+	c.store = "4336"
+	tests.Check(c.stats(c.Cmd(), nil))
+	r.Compare(t, `Orders:            1
+Total spent:       25.50
+Average order:     25.50
+First order:       2026-01-10
+Last order:        2026-01-10
+Favorite store:    4336
+Favorite product:  14SCREEN
+`)
+	r.ClearBuf()
+	c.store = ""
+
+	for _, tc := range []struct{ total, n, exp int64 }{{1001, 2, 501}, {1000, 3, 333}, {-1001, 2, -501}, {2, 3, 1}} {
+		if avg := average(dawg.Cents(tc.total), int(tc.n)); avg != dawg.Cents(tc.exp) {
+			t.Errorf("average(%d, %d) = %v, want %v", tc.total, tc.n, avg, dawg.Cents(tc.exp))
+		}
+	}
+
+	c.since, c.until = "2026-01-11", "2026-01-17"
+	tests.Check(c.list(c.Cmd(), nil))
+	if !r.Contains("lunch") || r.Contains("dinner") {
+		t.Errorf("wrong orders for the date range:\n%s", r.Out.String())
+	}
+	r.ClearBuf()
+	c.since = "last week"
+	tests.Exp(c.list(c.Cmd(), nil), "should not take a bad date")
+	c.since, c.until = "", ""
+
+	tests.Exp(c.show(c.Cmd(), nil))
+	tests.Check(c.show(c.Cmd(), []string{"aaaa"}))
+	r.Compare(t, `ID:      aaaa1111
+Name:    dinner
+Sent:    2026-01-10 18:30:00
+Store:   4336
+Method:  Delivery
+Products:
+  2x 14SCREEN
+Amounts:
+  Customer:  25.50
+  Tax:       1.50
+`)
+	r.ClearBuf()
+
+	tests.Check(c.reorder(c.Cmd(), []string{"bbbb"}))
+	o, err := data.GetOrder("lunch-again", r.DB())
+	tests.Check(err)
+	tests.StrEq(o.StoreID, "1111", "reordered cart should have the same store")
+}
@@ -28,7 +28,7 @@ type Recorder struct {
 //   - give the inner config an actual temp file and delete it in
 //     the CleanUp function. (need to get rid of global cfg var first)
 
-var services = []string{dawg.Carryout, dawg.Delivery}
+var services = []dawg.ServiceMethod{dawg.Carryout, dawg.Delivery}
 
 // NewRecorder create a new command recorder.
 func NewRecorder() *Recorder {
@@ -0,0 +1,206 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
+)
+
+const historyBucket = "history"
+
+// PlacedOrder is a record of an order that was successfully sent to dominos.
+type PlacedOrder struct {
+	// ID is the id of the placement that sent the order (see Placement).
+	ID            string
+	Name          string
+	Time          time.Time
+	StoreID       string
+	ServiceMethod dawg.ServiceMethod
+	Address       *dawg.StreetAddr
+	Products      []*dawg.OrderProduct
+	Coupons       []*dawg.OrderCoupon `json:",omitempty"`
+
+	// Amounts are the priced amounts of the order (see Order.PricedAmounts)
+	Amounts map[string]dawg.Money
+	// Tip is the tip for the delivery driver, which is not part of the
+	// priced amounts.
+	Tip dawg.Money
+	// Currency is the currency of the amounts and the tip. Orders saved
+	// without one are in dawg.DefaultCurrency.
+	Currency string `json:",omitempty"`
+	// Market is the code of the market that the order was sent to. Orders
+	// saved without one are in the market that uses their currency.
+	Market string `json:",omitempty"`
+
+	OrderID        string
+	PulseOrderGUID string
+
+	// PaymentType is the type of card used. Only the last four digits of
+	// the card are kept.
+	PaymentType  string
+	CardLastFour string
+}
+
+// NewPlacedOrder creates a history record for an order that was placed.
+func NewPlacedOrder(p *Placement, o *dawg.Order) *PlacedOrder {
+	po := &PlacedOrder{
+		ID:             p.ID,
+		Name:           o.Name(),
+		Time:           p.Time,
+		StoreID:        o.StoreID,
+		ServiceMethod:  o.ServiceMethod,
+		Address:        o.Address,
+		Products:       o.Products,
+		Coupons:        o.Coupons,
+		Amounts:        o.PricedAmounts(),
+		Tip:            o.Tip(),
+		Currency:       o.Market().Currency,
+		Market:         o.Market().Code,
+		OrderID:        o.OrderID,
+		PulseOrderGUID: o.PulseOrderGUID,
+	}
+	if len(o.Payments) > 0 {
+		payment := o.Payments[0]
+		po.PaymentType = payment.CardType
+		if len(payment.Number) >= 4 {
+			po.CardLastFour = payment.Number[len(payment.Number)-4:]
+		}
+	}
+	return po
+}
+
+// Total is the total price of the order including the tip.
+func (po *PlacedOrder) Total() dawg.Money {
+	return po.Amounts["Customer"].Add(po.Tip)
+}
+
+// setCurrency gives the amounts the order's currency after they are decoded,
+// since money is stored as a plain number.
+func (po *PlacedOrder) setCurrency() {
+	if po.Currency == "" {
+		po.Currency = dawg.DefaultCurrency
+	}
+	for k, amount := range po.Amounts {
+		po.Amounts[k] = amount.In(po.Currency)
+	}
+	po.Tip = po.Tip.In(po.Currency)
+}
+
+// ToOrder creates a new order with the same products and market as the
+// placed order. Coupons are not copied since most of them can only be used
+// once.
+func (po *PlacedOrder) ToOrder(name string) *dawg.Order {
+	o := &dawg.Order{
+		StoreID:       po.StoreID,
+		ServiceMethod: po.ServiceMethod,
+		Address:       po.Address,
+		Products:      po.Products,
+	}
+	o.Init()
+	o.SetMarket(po.market())
+	o.SetName(name)
+	return o
+}
+
+// market finds the market that the order was sent to.
+func (po *PlacedOrder) market() *dawg.Market {
+	if m, err := dawg.GetMarket(po.Market); err == nil {
+		return m
+	}
+	for _, m := range dawg.Markets {
+		if m.Currency == po.Currency {
+			return m
+		}
+	}
+	return dawg.UnitedStates
+}
+
+// HistoryFilter is used to select placed orders from the history.
+type HistoryFilter struct {
+	// Orders placed before Since or at or after Until are left out when
+	// either is set.
+	Since, Until time.Time
+	// StoreID limits the history to one store when set.
+	StoreID string
+	// Currency limits the history to orders paid in one currency when set,
+	// so that their totals can be added up.
+	Currency string
+}
+
+// Match tells whether the placed order passes the filter.
+func (f *HistoryFilter) Match(po *PlacedOrder) bool {
+	if !f.Since.IsZero() && po.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !po.Time.Before(f.Until) {
+		return false
+	}
+	if f.Currency != "" && f.Currency != po.Currency {
+		return false
+	}
+	return f.StoreID == "" || f.StoreID == po.StoreID
+}
+
+// SavePlacedOrder adds a placed order to the history.
+func SavePlacedOrder(db *cache.DataBase, po *PlacedOrder) error {
+	raw, err := json.Marshal(po)
+	if err != nil {
+		return err
+	}
+	return db.WithBucket(historyBucket).Put(po.ID, raw)
+}
+
+// PlacedOrders returns the placed orders that pass the filter, most recent
+// first. A nil filter will return every order.
+func PlacedOrders(db *cache.DataBase, filter *HistoryFilter) ([]*PlacedOrder, error) {
+	m, err := db.WithBucket(historyBucket).Map()
+	if err != nil {
+		return nil, err
+	}
+	orders := make([]*PlacedOrder, 0, len(m))
+	for _, raw := range m {
+		po := &PlacedOrder{}
+		if err = json.Unmarshal(raw, po); err != nil {
+			return nil, err
+		}
+		po.setCurrency()
+		if filter == nil || filter.Match(po) {
+			orders = append(orders, po)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].Time.After(orders[j].Time)
+	})
+	return orders, nil
+}
+
+// GetPlacedOrder finds a placed order by its id. Like git commits, any
+// unique prefix of the id can be used.
+func GetPlacedOrder(db *cache.DataBase, id string) (*PlacedOrder, error) {
+	if id == "" {
+		return nil, fmt.Errorf("no order id given")
+	}
+	orders, err := PlacedOrders(db, nil)
+	if err != nil {
+		return nil, err
+	}
+	var found *PlacedOrder
+	for _, po := range orders {
+		if !strings.HasPrefix(po.ID, id) {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("order id '%s' is ambiguous", id)
+		}
+		found = po
+	}
+	if found == nil {
+		return nil, fmt.Errorf("cannot find placed order '%s'", id)
+	}
+	return found, nil
+}
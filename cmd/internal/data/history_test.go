@@ -0,0 +1,92 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestHistory(t *testing.T) {
+	tests.InitHelpers(t)
+	db := cmdtest.TempDB()
+	defer db.Destroy()
+
+	o := &dawg.Order{StoreID: "4336", ServiceMethod: dawg.Delivery, OrderID: "abc123"}
+	o.Products = []*dawg.OrderProduct{{ItemCommon: dawg.ItemCommon{Code: "14SCREEN"}, Qty: 1}}
+	o.SetName("dinner")
+	o.AddCard(dawg.NewCard("4111111111111111", "01/30", 123))
+	p, err := NewPlacement(o)
+	tests.Check(err)
+	po := NewPlacedOrder(p, o)
+	tests.StrEq(po.CardLastFour, "1111", "wrong last four")
+	tests.StrEq(po.PaymentType, "Visa", "wrong payment type")
+	tests.StrEq(po.OrderID, "abc123", "wrong order id")
+	tests.Check(SavePlacedOrder(db, po))
+
+	jan := time.Date(2026, time.January, 10, 12, 0, 0, 0, time.Local)
+	tests.Check(SavePlacedOrder(db, &PlacedOrder{ID: "0a1b2c", StoreID: "1111", Time: jan}))
+	tests.Check(SavePlacedOrder(db, &PlacedOrder{ID: "0a9999", StoreID: "4336", Time: jan.AddDate(0, 1, 0)}))
+
+	all, err := PlacedOrders(db, nil)
+	tests.Check(err)
+	if len(all) != 3 || all[0].ID != po.ID || all[2].ID != "0a1b2c" {
+		t.Fatal("placed orders should be sorted newest first")
+	}
+	filtered, err := PlacedOrders(db, &HistoryFilter{StoreID: "4336"})
+	tests.Check(err)
+	if len(filtered) != 2 {
+		t.Errorf("expected 2 orders for store 4336; got %d", len(filtered))
+	}
+	filtered, err = PlacedOrders(db, &HistoryFilter{Since: jan, Until: jan.AddDate(0, 0, 1)})
+	tests.Check(err)
+	if len(filtered) != 1 || filtered[0].ID != "0a1b2c" {
+		t.Error("wrong orders for the date range")
+	}
+
+	tests.StrEq(all[0].Currency, "USD", "wrong currency for a us order")
+	if all[2].Currency != dawg.DefaultCurrency {
+		t.Error("orders saved without a currency should be in the default currency")
+	}
+	tests.Check(SavePlacedOrder(db, &PlacedOrder{ID: "ca1234", Time: jan, Currency: "CAD",
+		Amounts: map[string]dawg.Money{"Customer": dawg.Cents(1000).In("CAD")}}))
+	filtered, err = PlacedOrders(db, &HistoryFilter{Currency: "CAD"})
+	tests.Check(err)
+	if len(filtered) != 1 || filtered[0].Total() != dawg.Cents(1000).In("CAD") {
+		t.Error("should only find the canadian order in canadian dollars")
+	}
+	tests.Check(db.WithBucket(historyBucket).Delete("ca1234"))
+
+	found, err := GetPlacedOrder(db, "0a1")
+	tests.Check(err)
+	tests.StrEq(found.ID, "0a1b2c", "should find orders by prefix")
+	_, err = GetPlacedOrder(db, "0a")
+	tests.Exp(err, "prefix should be ambiguous")
+	_, err = GetPlacedOrder(db, "zzz")
+	tests.Exp(err, "should not find an order")
+
+	reorder := po.ToOrder("again")
+	tests.StrEq(reorder.Name(), "again", "wrong name")
+	tests.StrEq(reorder.StoreID, "4336", "wrong store")
+	if len(reorder.Products) != 1 || len(reorder.Payments) != 0 {
+		t.Error("reorder should have the products and no payments")
+	}
+	tests.StrEq(po.Market, "US", "wrong market")
+	if reorder.Market() != dawg.UnitedStates {
+		t.Error("reorder should be in the same market")
+	}
+
+	o.SetMarket(dawg.Canada)
+	ca := NewPlacedOrder(p, o)
+	tests.StrEq(ca.Market, "CA", "wrong market for a canadian order")
+	if reorder = ca.ToOrder("again"); reorder.Market() != dawg.Canada {
+		t.Error("canadian orders should be reordered in canada")
+	}
+	tests.StrEq(reorder.LanguageCode, dawg.Canada.Lang, "wrong language")
+	old := &PlacedOrder{ID: "ca5678", Currency: "CAD"}
+	if old.ToOrder("old").Market() != dawg.Canada {
+		t.Error("orders saved without a market should use the market of their currency")
+	}
+}
@@ -95,14 +95,14 @@ func TestMenuCacherJSON(t *testing.T) {
 		t.Error("cacher should not have a menu yet")
 	}
 
-	tests.Check(db.UpdateTS("menu", cacher))
+	tests.Check(cacher.UpdateMenu())
 	if c.m == nil {
 		t.Error("cacher should have a menu now")
 	}
 	if cacher.Menu() == nil {
 		t.Error("cacher should have a menu now")
 	}
-	data, err := db.Get("menu")
+	data, err := db.Get(menuKey(testStore.ID))
 	tests.Check(err)
 	if len(data) == 0 {
 		t.Error("should have stored a menu")
@@ -113,7 +113,7 @@ func TestMenuCacherJSON(t *testing.T) {
 	}
 	buf.Reset()
 
-	tests.Check(db.UpdateTS("menu", cacher))
+	tests.Check(cacher.UpdateMenu())
 	c.m = nil
-	tests.Check(db.UpdateTS("menu", c))
+	tests.Check(c.UpdateMenu())
 }
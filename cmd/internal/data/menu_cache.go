@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"encoding/gob"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/harrybrwn/apizza/dawg"
@@ -15,15 +17,43 @@ import (
 
 // MenuCacher defines an interface that retrieves, caches, and stores
 // menu timestamps.
+//
+// Menus are cached for each store with their own timestamps, so switching
+// between addresses does not mean downloading a new menu every time. Only
+// the menus of the MaxCachedMenus most recently used stores are kept.
 type MenuCacher interface {
 	cache.Updater
 	Menu() *dawg.Menu
+
+	// UpdateMenu loads the menu for the current store, downloading a new one
+	// if the cached menu is too old. In offline mode (see dawg.SetOffline)
+	// only the cached menu is used.
+	UpdateMenu() error
+
+	// PreviousMenu returns the menu that the current store's menu replaced
+	// the last time it was refreshed. Returns nil if there is no older menu.
+	PreviousMenu() (*dawg.Menu, error)
+
+	// Changes returns the changes to the menu if it was refreshed since the
+	// cacher was created and nil if it was not.
+	Changes() *dawg.MenuDiff
+}
+
+// MenuStorage is the storage that menus are cached in.
+type MenuStorage interface {
+	cache.Storage
+	cache.Deleter
+	UpdateTS(string, cache.Updater) error
+	DeleteTimeStamp(string) error
 }
 
+// MaxCachedMenus is the number of store menus that are kept in the cache.
+var MaxCachedMenus = 5
+
 // NewMenuCacher creates a new MenuCacher.
 func NewMenuCacher(
 	decay time.Duration,
-	db cache.Storage,
+	db MenuStorage,
 	store func() *dawg.Store,
 ) MenuCacher {
 	// use gob to cache the menu in binary format
@@ -49,8 +79,9 @@ type Decoder interface {
 type generalMenuCacher struct {
 	cache.Updater
 	m        *dawg.Menu
-	db       cache.Storage
+	db       MenuStorage
 	getstore func() *dawg.Store
+	changes  *dawg.MenuDiff
 
 	newEncoder func(io.Writer) Encoder
 	newDecoder func(io.Reader) Decoder
@@ -60,7 +91,7 @@ type generalMenuCacher struct {
 // menu as json.
 func NewJSONMenuCacher(
 	decay time.Duration,
-	db cache.Storage,
+	db MenuStorage,
 	store func() *dawg.Store,
 ) MenuCacher {
 	mc := &generalMenuCacher{
@@ -78,7 +109,7 @@ func NewJSONMenuCacher(
 // in a binary format using the "encoding/gob" package.
 func NewGobMenuCacher(
 	decay time.Duration,
-	db cache.Storage,
+	db MenuStorage,
 	store func() *dawg.Store,
 ) MenuCacher {
 	mc := &generalMenuCacher{
@@ -99,35 +130,205 @@ func (mc *generalMenuCacher) Menu() *dawg.Menu {
 	return nil
 }
 
+func (mc *generalMenuCacher) UpdateMenu() error {
+	if dawg.IsOffline() {
+		return mc.getCachedMenu()
+	}
+	return mc.db.UpdateTS(menuKey(mc.getstore().ID), mc)
+}
+
+func (mc *generalMenuCacher) PreviousMenu() (*dawg.Menu, error) {
+	raw, err := mc.db.Get(previousMenuKey(mc.getstore().ID))
+	if raw == nil {
+		return nil, err
+	}
+	m := new(dawg.Menu)
+	return m, errs.Pair(err, mc.newDecoder(bytes.NewBuffer(raw)).Decode(m))
+}
+
+func (mc *generalMenuCacher) Changes() *dawg.MenuDiff {
+	return mc.changes
+}
+
+func menuKey(storeID string) string {
+	return "menu/" + storeID
+}
+
+func menuIndexKey(storeID string) string {
+	return menuKey(storeID) + "/index"
+}
+
+func previousMenuKey(storeID string) string {
+	return menuKey(storeID) + "/previous"
+}
+
+// the list of store ids with cached menus, most recently used first
+const menuListKey = "menu-stores"
+
 func (mc *generalMenuCacher) cacheNewMenu() error {
-	var e1, e2 error
-	mc.m, e1 = mc.getstore().Menu()
+	store := mc.getstore()
+	old, err := mc.keepOldMenu(store.ID)
+	if err != nil {
+		return err
+	}
+
+	m, err := store.Menu()
+	if err != nil {
+		return err
+	}
 	log.Println("caching another menu")
+	mc.m = m
+	if old != nil {
+		mc.changes = dawg.DiffMenus(old, mc.m)
+	}
 
 	buf := &bytes.Buffer{}
-	e2 = mc.newEncoder(buf).Encode(mc.m)
-	return errs.Append(e1, e2, mc.db.Put("menu", buf.Bytes()))
+	err = mc.newEncoder(buf).Encode(mc.m)
+	if err != nil {
+		return err
+	}
+	return errs.Append(
+		mc.db.Put(menuKey(store.ID), buf.Bytes()),
+		mc.cacheIndex(store.ID),
+		mc.useStore(store.ID),
+	)
 }
 
-func (mc *generalMenuCacher) getCachedMenu() error {
+// keepOldMenu saves the cached menu for a store as its previous menu
+// before it is replaced.
+func (mc *generalMenuCacher) keepOldMenu(id string) (*dawg.Menu, error) {
+	raw, err := mc.db.Get(menuKey(id))
+	if raw == nil {
+		return nil, err
+	}
+	old := new(dawg.Menu)
+	if mc.newDecoder(bytes.NewBuffer(raw)).Decode(old) != nil || old.ID != id {
+		return nil, nil // a broken menu is not worth keeping
+	}
+	return old, mc.db.Put(previousMenuKey(id), raw)
+}
+
+// cacheIndex stores the index of the current menu so that it does not have
+// to be rebuilt every time the menu is loaded from the cache.
+func (mc *generalMenuCacher) cacheIndex(id string) error {
 	if mc.m == nil {
-		mc.m = new(dawg.Menu)
-		raw, err := mc.db.Get("menu")
-		if raw == nil {
-			return mc.cacheNewMenu()
+		return nil
+	}
+	buf := &bytes.Buffer{}
+	if err := mc.newEncoder(buf).Encode(mc.m.Index()); err != nil {
+		return err
+	}
+	return mc.db.Put(menuIndexKey(id), buf.Bytes())
+}
+
+// loadIndex gives the current menu its cached index. If the cached index
+// is missing or was made from a different menu, a new one is built and
+// cached.
+func (mc *generalMenuCacher) loadIndex(id string) error {
+	raw, err := mc.db.Get(menuIndexKey(id))
+	if err != nil {
+		return err
+	}
+	if raw != nil {
+		idx := new(dawg.MenuIndex)
+		if mc.newDecoder(bytes.NewBuffer(raw)).Decode(idx) == nil {
+			mc.m.SetIndex(idx)
+			if mc.m.Index() == idx {
+				return nil
+			}
+		}
+	}
+	return mc.cacheIndex(id)
+}
+
+func (mc *generalMenuCacher) getCachedMenu() error {
+	id := mc.getstore().ID
+	if mc.m != nil && mc.m.ID == id {
+		return nil
+	}
+	raw, err := mc.db.Get(menuKey(id))
+	if err != nil {
+		return err
+	}
+	if raw == nil {
+		if dawg.IsOffline() {
+			return fmt.Errorf("no menu has been saved for store %s; run apizza without --offline to download it", id)
 		}
+		return mc.cacheNewMenu()
+	}
 
-		dec := mc.newDecoder(bytes.NewBuffer(raw))
-		err = errs.Pair(err, dec.Decode(mc.m))
-		if err != nil {
+	m := new(dawg.Menu)
+	err = mc.newDecoder(bytes.NewBuffer(raw)).Decode(m)
+	if err == nil && m.ID != id {
+		err = fmt.Errorf("the menu saved for store %s is for store %s", id, m.ID)
+	}
+	if err != nil {
+		if dawg.IsOffline() {
 			return err
 		}
+		return mc.cacheNewMenu()
+	}
+	mc.m = m
+	return errs.Pair(mc.loadIndex(id), mc.useStore(id))
+}
+
+// useStore moves a store to the front of the list of cached menus and
+// removes the menus of the least recently used stores.
+func (mc *generalMenuCacher) useStore(id string) error {
+	ids, err := cachedMenuStores(mc.db)
+	if err != nil {
+		return err
+	}
+	if len(ids) > 0 && ids[0] == id {
+		return nil
+	}
+	list := []string{id}
+	for _, other := range ids {
+		if other != id {
+			list = append(list, other)
+		}
+	}
+	if len(list) > MaxCachedMenus && MaxCachedMenus > 0 {
+		for _, old := range list[MaxCachedMenus:] {
+			if err = deleteStoreMenu(mc.db, old); err != nil {
+				return err
+			}
+		}
+		list = list[:MaxCachedMenus]
+	}
+	return mc.db.Put(menuListKey, []byte(strings.Join(list, ",")))
+}
 
-		if mc.m.ID != mc.getstore().ID {
-			return mc.cacheNewMenu()
+func cachedMenuStores(db cache.Getter) ([]string, error) {
+	raw, err := db.Get(menuListKey)
+	if len(raw) == 0 {
+		return nil, err
+	}
+	return strings.Split(string(raw), ","), err
+}
+
+func deleteStoreMenu(db MenuStorage, id string) error {
+	return errs.Append(
+		db.Delete(menuKey(id)),
+		db.Delete(menuIndexKey(id)),
+		db.Delete(previousMenuKey(id)),
+		db.DeleteTimeStamp(menuKey(id)),
+	)
+}
+
+// DeleteMenus removes every cached menu.
+func DeleteMenus(db MenuStorage) error {
+	ids, err := cachedMenuStores(db)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err = deleteStoreMenu(db, id); err != nil {
+			return err
 		}
 	}
-	return nil
+	// older versions only cached one menu
+	return errs.Append(db.Delete(menuListKey), db.Delete("menu"), db.DeleteTimeStamp("menu"))
 }
 
 var _ MenuCacher = (*generalMenuCacher)(nil)
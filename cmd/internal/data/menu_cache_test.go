@@ -0,0 +1,211 @@
+package data
+
+import (
+	"bytes"
+	"encoding/gob"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestMenuCacherIndex(t *testing.T) {
+	tests.InitHelpers(t)
+	db := cmdtest.TempDB()
+	defer db.Destroy()
+
+	menu := &dawg.Menu{
+		ID: "4336",
+		Products: map[string]*dawg.Product{
+			"S_PIZZA": {
+				ItemCommon: dawg.ItemCommon{Code: "S_PIZZA", Name: "Hand Tossed"},
+				Variants:   []string{"12SCREEN"},
+			},
+		},
+		Variants: map[string]*dawg.Variant{
+			"12SCREEN": {ItemCommon: dawg.ItemCommon{Code: "12SCREEN", Name: "Medium Hand Tossed"}, ProductCode: "S_PIZZA"},
+		},
+	}
+	encode := func(key string, v interface{}) {
+		buf := &bytes.Buffer{}
+		tests.Check(gob.NewEncoder(buf).Encode(v))
+		tests.Check(db.Put(key, buf.Bytes()))
+	}
+	encode(menuKey("4336"), menu)
+	store := func() *dawg.Store { return &dawg.Store{ID: "4336"} }
+
+	// no cached index
+	mc := NewGobMenuCacher(time.Hour, db, store).(*generalMenuCacher)
+	tests.Check(mc.getCachedMenu())
+	if mc.Menu().Index().Kind("12SCREEN") != dawg.VariantItem {
+		t.Error("the index should have been built")
+	}
+	raw, err := db.Get(menuIndexKey("4336"))
+	tests.Check(err)
+	if raw == nil {
+		t.Fatal("the index should have been cached")
+	}
+
+	// cached index is used
+	idx := dawg.NewMenuIndex(menu)
+	idx.Tokens["cached"] = []string{"S_PIZZA"}
+	encode(menuIndexKey("4336"), idx)
+	mc = NewGobMenuCacher(time.Hour, db, store).(*generalMenuCacher)
+	tests.Check(mc.getCachedMenu())
+	if codes := mc.Menu().Index().Lookup("cached"); len(codes) != 1 || codes[0] != "S_PIZZA" {
+		t.Error("the cached index should have been used")
+	}
+	if _, ok := mc.Menu().FindItem("12SCREEN").(*dawg.Variant); !ok {
+		t.Error("should find variants with a cached index")
+	}
+
+	// index for another menu is replaced
+	idx.MenuID = "1111"
+	encode(menuIndexKey("4336"), idx)
+	mc = NewGobMenuCacher(time.Hour, db, store).(*generalMenuCacher)
+	tests.Check(mc.getCachedMenu())
+	if mc.Menu().Index().Lookup("cached") != nil {
+		t.Error("an index from a different menu should not be used")
+	}
+	if mc.Menu().Index().MenuID != "4336" {
+		t.Error("wrong menu id for the new index")
+	}
+
+	// index for an older menu from the same store is replaced
+	older := &dawg.Menu{ID: "4336", Products: map[string]*dawg.Product{
+		"S_OLD": {ItemCommon: dawg.ItemCommon{Code: "S_OLD", Name: "Old Pizza"}},
+	}}
+	encode(menuIndexKey("4336"), dawg.NewMenuIndex(older))
+	mc = NewGobMenuCacher(time.Hour, db, store).(*generalMenuCacher)
+	tests.Check(mc.getCachedMenu())
+	if mc.Menu().Index().Kind("S_OLD") != 0 || mc.Menu().Index().Kind("S_PIZZA") != dawg.ProductItem {
+		t.Error("an index from an older menu should not be used")
+	}
+	if res := mc.Menu().Search("old pizza", dawg.SearchOptions{}); len(res) != 0 {
+		t.Errorf("should not find items from the older menu: %v", res)
+	}
+}
+
+func TestMenuCacherPrevious(t *testing.T) {
+	tests.InitHelpers(t)
+	db := cmdtest.TempDB()
+	defer db.Destroy()
+	store := &dawg.Store{ID: "4336"}
+	mc := NewGobMenuCacher(time.Hour, db, func() *dawg.Store { return store }).(*generalMenuCacher)
+
+	prev, err := mc.PreviousMenu()
+	tests.Check(err)
+	if prev != nil {
+		t.Error("there should not be a previous menu yet")
+	}
+	old, err := mc.keepOldMenu("4336")
+	tests.Check(err)
+	if old != nil {
+		t.Error("there is no menu to keep")
+	}
+
+	buf := &bytes.Buffer{}
+	tests.Check(gob.NewEncoder(buf).Encode(&dawg.Menu{ID: "4336", Products: map[string]*dawg.Product{
+		"S_PIZZA": {ItemCommon: dawg.ItemCommon{Code: "S_PIZZA", Name: "Hand Tossed"}},
+	}}))
+	tests.Check(db.Put(menuKey("4336"), buf.Bytes()))
+	old, err = mc.keepOldMenu("4336")
+	tests.Check(err)
+	if old == nil || old.ID != "4336" {
+		t.Fatal("should have kept the old menu")
+	}
+
+	prev, err = mc.PreviousMenu()
+	tests.Check(err)
+	if prev == nil || prev.Products["S_PIZZA"] == nil {
+		t.Fatal("should have gotten the previous menu")
+	}
+	store.ID = "1111"
+	prev, err = mc.PreviousMenu()
+	tests.Check(err)
+	if prev != nil {
+		t.Error("previous menus are kept for each store")
+	}
+	if mc.Changes() != nil {
+		t.Error("there should be no changes before a refresh")
+	}
+}
+
+func TestMenuCacherStores(t *testing.T) {
+	tests.InitHelpers(t)
+	db := cmdtest.TempDB()
+	defer db.Destroy()
+	defer func(n int) { MaxCachedMenus = n }(MaxCachedMenus)
+	MaxCachedMenus = 2
+
+	store := &dawg.Store{}
+	mc := NewGobMenuCacher(time.Hour, db, func() *dawg.Store { return store }).(*generalMenuCacher)
+	for _, id := range []string{"1", "2", "3"} {
+		buf := &bytes.Buffer{}
+		tests.Check(gob.NewEncoder(buf).Encode(&dawg.Menu{ID: id}))
+		tests.Check(db.Put(menuKey(id), buf.Bytes()))
+		tests.Check(db.ResetTimeStamp(menuKey(id)))
+	}
+
+	// switching stores uses the menu cached for each store
+	for _, id := range []string{"1", "2", "1"} {
+		store.ID = id
+		tests.Check(mc.UpdateMenu())
+		if mc.Menu().ID != id {
+			t.Fatalf("got the menu for store %s, want %s", mc.Menu().ID, id)
+		}
+	}
+	ids, err := cachedMenuStores(db)
+	tests.Check(err)
+	tests.StrEq(strings.Join(ids, ","), "1,2", "wrong store order")
+
+	store.ID = "3"
+	tests.Check(mc.UpdateMenu())
+	ids, err = cachedMenuStores(db)
+	tests.Check(err)
+	tests.StrEq(strings.Join(ids, ","), "3,1", "least recently used store should be removed")
+	if db.Exists(menuKey("2")) || db.Exists(menuIndexKey("2")) {
+		t.Error("menu for store 2 should have been deleted")
+	}
+	if !db.Exists(menuIndexKey("3")) {
+		t.Error("index should have been cached")
+	}
+
+	tests.Check(DeleteMenus(db))
+	for _, key := range []string{menuKey("1"), menuKey("3"), menuListKey} {
+		if db.Exists(key) {
+			t.Errorf("%s should have been deleted", key)
+		}
+	}
+}
+
+func TestMenuCacherOffline(t *testing.T) {
+	tests.InitHelpers(t)
+	db := cmdtest.TempDB()
+	defer db.Destroy()
+	dawg.SetOffline(true)
+	defer dawg.SetOffline(false)
+
+	store := &dawg.Store{ID: "4336"}
+	mc := NewGobMenuCacher(time.Nanosecond, db, func() *dawg.Store { return store }).(*generalMenuCacher)
+	tests.Exp(mc.UpdateMenu(), "should fail when there is no cached menu")
+
+	buf := &bytes.Buffer{}
+	tests.Check(gob.NewEncoder(buf).Encode(&dawg.Menu{ID: "4336"}))
+	tests.Check(db.Put(menuKey("4336"), buf.Bytes()))
+	tests.Check(db.ResetTimeStamp(menuKey("4336")))
+	time.Sleep(time.Millisecond)
+
+	// the cached menu is old but offline mode should not try to update it
+	tests.Check(mc.UpdateMenu())
+	if mc.Menu() == nil || mc.Menu().ID != "4336" {
+		t.Error("should have used the cached menu")
+	}
+
+	tests.Check(db.Put(menuKey("4336"), []byte("not a menu")))
+	mc.m = nil
+	tests.Exp(mc.UpdateMenu(), "should fail with a broken menu")
+}
@@ -0,0 +1,165 @@
+package data
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
+)
+
+const placementBucket = "placements"
+
+// Placement statuses.
+const (
+	// PlacementSending is the status of a placement that has not gotten a
+	// response from dominos yet. If a placement still has this status then
+	// the program was stopped while the order was being sent.
+	PlacementSending = "sending"
+	// PlacementUnknown is the status of a placement that failed without a
+	// response from dominos, like on a timeout. The order may or may not
+	// have been received.
+	PlacementUnknown = "unknown"
+	// PlacementRejected is the status of a placement that dominos sent back
+	// an error for.
+	PlacementRejected = "rejected"
+	// PlacementPlaced is the status of a placement that was successful.
+	PlacementPlaced = "placed"
+)
+
+// Placement is a record of one attempt at sending an order to dominos.
+type Placement struct {
+	// ID is a random id that is unique to each placement.
+	ID string
+	// Hash is the hash of the contents of the order (see CartHash).
+	Hash      string
+	OrderName string
+	StoreID   string
+	Phone     string
+	Time      time.Time
+	Status    string
+	Error     string `json:",omitempty"`
+
+	// OrderID and PulseOrderGUID are the ids that dominos gave the order.
+	OrderID        string
+	PulseOrderGUID string
+}
+
+// NewPlacement creates a new placement record for an order that is about to
+// be sent.
+func NewPlacement(o *dawg.Order) (*Placement, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	return &Placement{
+		ID:        hex.EncodeToString(id),
+		Hash:      CartHash(o),
+		OrderName: o.Name(),
+		StoreID:   o.StoreID,
+		Phone:     o.Phone,
+		Time:      time.Now(),
+		Status:    PlacementSending,
+	}, nil
+}
+
+// Update will update the placement with the results of sending the order.
+func (p *Placement) Update(o *dawg.Order, err error) {
+	p.OrderID = o.OrderID
+	p.PulseOrderGUID = o.PulseOrderGUID
+	switch {
+	case err == nil:
+		p.Status = PlacementPlaced
+		p.Error = ""
+	case dawg.IsWarning(err):
+		// dominos still takes orders with warnings
+		p.Status = PlacementPlaced
+		p.Error = err.Error()
+	case dawg.IsFailure(err):
+		p.Status = PlacementRejected
+		p.Error = err.Error()
+	default:
+		p.Status = PlacementUnknown
+		p.Error = err.Error()
+	}
+}
+
+// Blocking tells whether the placement should stop the same order from being
+// sent again.
+func (p *Placement) Blocking() bool {
+	return p.Status != PlacementRejected
+}
+
+// CartHash returns a hash of everything in an order that decides what will
+// show up at the door and when: the store, service method, address, products,
+// coupons, and future order time. The order's name and payments are not
+// included.
+func CartHash(o *dawg.Order) string {
+	raw, _ := json.Marshal(struct {
+		StoreID       string
+		ServiceMethod dawg.ServiceMethod
+		Address       *dawg.StreetAddr
+		Products      []*dawg.OrderProduct
+		Coupons       []*dawg.OrderCoupon
+		// left out when empty so that the hash of an order made right
+		// away does not change
+		FutureOrderTime string `json:",omitempty"`
+	}{o.StoreID, o.ServiceMethod, o.Address, o.Products, o.Coupons, o.FutureOrderTime})
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// SavePlacement stores the placement record in the database.
+func SavePlacement(db *cache.DataBase, p *Placement) error {
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return db.WithBucket(placementBucket).Put(p.ID, raw)
+}
+
+// Placements returns all of the placement records sorted by time, with the
+// most recent first.
+func Placements(db *cache.DataBase) ([]*Placement, error) {
+	m, err := db.WithBucket(placementBucket).Map()
+	if err != nil {
+		return nil, err
+	}
+	placements := make([]*Placement, 0, len(m))
+	for _, raw := range m {
+		p := &Placement{}
+		if err = json.Unmarshal(raw, p); err != nil {
+			return nil, err
+		}
+		placements = append(placements, p)
+	}
+	sort.Slice(placements, func(i, j int) bool {
+		return placements[i].Time.After(placements[j].Time)
+	})
+	return placements, nil
+}
+
+// RecentPlacement finds the most recent placement of an order with the same
+// contents that was sent within the time window and that could have gone
+// through. It returns nil if there is none.
+func RecentPlacement(db *cache.DataBase, o *dawg.Order, window time.Duration) (*Placement, error) {
+	placements, err := Placements(db)
+	if err != nil {
+		return nil, err
+	}
+	hash := CartHash(o)
+	since := time.Now().Add(-window)
+	for _, p := range placements {
+		if p.Time.Before(since) {
+			break
+		}
+		if p.Hash == hash && p.Blocking() {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
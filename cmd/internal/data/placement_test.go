@@ -0,0 +1,89 @@
+package data
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestPlacements(t *testing.T) {
+	tests.InitHelpers(t)
+	db := cmdtest.TempDB()
+	defer db.Destroy()
+
+	o := &dawg.Order{StoreID: "4336", ServiceMethod: dawg.Carryout, Phone: "2025550123"}
+	o.Products = []*dawg.OrderProduct{{ItemCommon: dawg.ItemCommon{Code: "14SCREEN"}, Qty: 1}}
+	o.SetName("dinner")
+	other := &dawg.Order{StoreID: "4336", ServiceMethod: dawg.Carryout}
+	other.Products = []*dawg.OrderProduct{{ItemCommon: dawg.ItemCommon{Code: "14SCREEN"}, Qty: 2}}
+
+	hash := CartHash(o)
+	if hash == CartHash(other) {
+		t.Error("orders with different products should have different hashes")
+	}
+	o.AddCard(dawg.NewCard("4111111111111111", "01/30", 123))
+	tests.StrEq(CartHash(o), hash, "payments should not change the cart hash")
+
+	p, err := NewPlacement(o)
+	tests.Check(err)
+	tests.StrEq(p.Status, PlacementSending, "new placements should be sending")
+	tests.StrEq(p.OrderName, "dinner", "wrong order name")
+	tests.Check(SavePlacement(db, p))
+
+	prev, err := RecentPlacement(db, o, time.Hour)
+	tests.Check(err)
+	if prev == nil || prev.ID != p.ID {
+		t.Fatal("should find the placement that is still sending")
+	}
+	prev, err = RecentPlacement(db, other, time.Hour)
+	tests.Check(err)
+	if prev != nil {
+		t.Error("should not find placements for a different order")
+	}
+
+	o.OrderID, o.PulseOrderGUID = "abc123", "guid"
+	p.Update(o, errors.New("timeout"))
+	tests.StrEq(p.Status, PlacementUnknown, "network errors should give an unknown status")
+	tests.StrEq(p.OrderID, "abc123", "should keep the order id")
+	p.Update(o, &dawg.DominosError{Status: dawg.FailureStatus})
+	tests.StrEq(p.Status, PlacementRejected, "dominos errors should reject the placement")
+	tests.Check(SavePlacement(db, p))
+	prev, err = RecentPlacement(db, o, time.Hour)
+	tests.Check(err)
+	if prev != nil {
+		t.Error("rejected placements should not block the order")
+	}
+
+	old := &Placement{ID: "old", Hash: hash, Time: time.Now().Add(-2 * time.Hour), Status: PlacementPlaced}
+	tests.Check(SavePlacement(db, old))
+	p.Update(o, nil)
+	tests.StrEq(p.Status, PlacementPlaced, "wrong status")
+	tests.StrEq(p.PulseOrderGUID, "guid", "should keep the pulse guid")
+	tests.Check(SavePlacement(db, p))
+
+	all, err := Placements(db)
+	tests.Check(err)
+	if len(all) != 2 || all[0].ID != p.ID {
+		t.Error("placements should be sorted newest first")
+	}
+	prev, err = RecentPlacement(db, o, time.Hour)
+	tests.Check(err)
+	if prev == nil || prev.ID != p.ID {
+		t.Error("should find the placed order")
+	}
+	prev, err = RecentPlacement(db, o, time.Minute)
+	tests.Check(err)
+	if prev == nil {
+		t.Error("the new placement is within a minute")
+	}
+	tests.Check(db.WithBucket(placementBucket).Delete(p.ID))
+	prev, err = RecentPlacement(db, o, time.Hour)
+	tests.Check(err)
+	if prev != nil {
+		t.Error("placements outside of the window should not block the order")
+	}
+}
@@ -0,0 +1,184 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
+	"github.com/harrybrwn/apizza/pkg/cron"
+)
+
+const (
+	scheduleBucket    = "schedules"
+	scheduleRunBucket = "schedule_runs"
+)
+
+// Schedule statuses.
+const (
+	// SchedulePlaced is the status of a scheduled run that sent the order.
+	SchedulePlaced = "placed"
+	// SchedulePriced is the status of a dry run, which only prices the order.
+	SchedulePriced = "priced"
+	// ScheduleMissed is the status of a schedule that had runs come due while
+	// the scheduler was not running. Missed runs are never placed late.
+	ScheduleMissed = "missed"
+	// ScheduleFailed is the status of a scheduled run that did not go through.
+	ScheduleFailed = "failed"
+)
+
+// Schedule is a cart that is sent to dominos on a cron schedule.
+type Schedule struct {
+	// Cart is the name of the saved order that is sent.
+	Cart string
+	// Cron is the cron expression for when to send the order (see pkg/cron).
+	Cron    string
+	Created time.Time
+
+	// LastRun is the last time the scheduler checked the schedule. Runs
+	// between LastRun and the next check are due.
+	LastRun    time.Time
+	LastStatus string `json:",omitempty"`
+	LastError  string `json:",omitempty"`
+}
+
+// NewSchedule creates a new schedule for a cart. An error is returned if the
+// cron expression cannot be parsed.
+func NewSchedule(cart, spec string) (*Schedule, error) {
+	if _, err := cron.Parse(spec); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &Schedule{Cart: cart, Cron: spec, Created: now, LastRun: now}, nil
+}
+
+// Parse parses the schedule's cron expression.
+func (s *Schedule) Parse() (*cron.Schedule, error) {
+	return cron.Parse(s.Cron)
+}
+
+// Next returns the next time that the schedule is due after the last run.
+func (s *Schedule) Next() time.Time {
+	c, err := s.Parse()
+	if err != nil {
+		return time.Time{}
+	}
+	return c.Next(s.LastRun)
+}
+
+// SaveSchedule stores a schedule in the database. There can only be one
+// schedule for each cart.
+func SaveSchedule(db *cache.DataBase, s *Schedule) error {
+	raw, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return db.WithBucket(scheduleBucket).Put(s.Cart, raw)
+}
+
+// GetSchedule gets the schedule for a cart.
+func GetSchedule(db *cache.DataBase, cart string) (*Schedule, error) {
+	raw, err := db.WithBucket(scheduleBucket).Get(cart)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("cart '%s' is not scheduled", cart)
+	}
+	s := &Schedule{}
+	return s, json.Unmarshal(raw, s)
+}
+
+// DeleteSchedule removes the schedule for a cart.
+func DeleteSchedule(db *cache.DataBase, cart string) error {
+	if _, err := GetSchedule(db, cart); err != nil {
+		return err
+	}
+	return db.WithBucket(scheduleBucket).Delete(cart)
+}
+
+// Schedules returns all of the schedules sorted by cart name.
+func Schedules(db *cache.DataBase) ([]*Schedule, error) {
+	m, err := db.WithBucket(scheduleBucket).Map()
+	if err != nil {
+		return nil, err
+	}
+	schedules := make([]*Schedule, 0, len(m))
+	for _, raw := range m {
+		s := &Schedule{}
+		if err = json.Unmarshal(raw, s); err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, s)
+	}
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].Cart < schedules[j].Cart
+	})
+	return schedules, nil
+}
+
+// ScheduleRun is a record of the outcome of one scheduled run, which is kept
+// for every run whether the order was sent, priced, missed, or failed.
+type ScheduleRun struct {
+	Cart string
+	// Due is when the run was due. For missed runs it is the last run that
+	// was missed.
+	Due time.Time
+	// Time is when the scheduler handled the run.
+	Time   time.Time
+	Status string
+	Error  string `json:",omitempty"`
+
+	// Missed is the number of runs that were missed.
+	Missed int `json:",omitempty"`
+	// Price is the price of the order for a dry run.
+	Price dawg.Money
+	// Currency is the currency of the price.
+	Currency string `json:",omitempty"`
+	// OrderID is the id that dominos gave a placed order, which can be
+	// found in the order history.
+	OrderID string `json:",omitempty"`
+}
+
+// SaveScheduleRun adds a scheduled run to the schedule history.
+func SaveScheduleRun(db *cache.DataBase, r *ScheduleRun) error {
+	if r.Currency == "" {
+		r.Currency = r.Price.Currency
+	}
+	raw, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	key := r.Time.UTC().Format("20060102T150405.000000000") + "/" + r.Cart
+	return db.WithBucket(scheduleRunBucket).Put(key, raw)
+}
+
+// ScheduleRuns returns the scheduled runs of a cart, most recent first. All
+// of the runs are returned if the cart is empty.
+func ScheduleRuns(db *cache.DataBase, cart string) ([]*ScheduleRun, error) {
+	m, err := db.WithBucket(scheduleRunBucket).Map()
+	if err != nil {
+		return nil, err
+	}
+	runs := make([]*ScheduleRun, 0, len(m))
+	for _, raw := range m {
+		r := &ScheduleRun{}
+		if err = json.Unmarshal(raw, r); err != nil {
+			return nil, err
+		}
+		if cart != "" && r.Cart != cart {
+			continue
+		}
+		if r.Currency == "" {
+			r.Currency = dawg.DefaultCurrency
+		}
+		r.Price = r.Price.In(r.Currency)
+		runs = append(runs, r)
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].Time.After(runs[j].Time)
+	})
+	return runs, nil
+}
@@ -0,0 +1,66 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestSchedules(t *testing.T) {
+	tests.InitHelpers(t)
+	db := cmdtest.TempDB()
+	defer db.Destroy()
+
+	_, err := NewSchedule("lunch", "every friday")
+	tests.Exp(err, "should not take a bad cron expression")
+	s, err := NewSchedule("lunch", "0 12 * * FRI")
+	tests.Check(err)
+	s.LastRun = time.Date(2026, time.October, 18, 9, 0, 0, 0, time.Local)
+	if !s.Next().Equal(time.Date(2026, time.October, 23, 12, 0, 0, 0, time.Local)) {
+		t.Errorf("wrong next run: %v", s.Next())
+	}
+	tests.Check(SaveSchedule(db, s))
+	tests.Check(SaveSchedule(db, &Schedule{Cart: "dinner", Cron: "@daily"}))
+
+	all, err := Schedules(db)
+	tests.Check(err)
+	if len(all) != 2 || all[0].Cart != "dinner" || all[1].Cart != "lunch" {
+		t.Fatal("schedules should be sorted by cart")
+	}
+	got, err := GetSchedule(db, "lunch")
+	tests.Check(err)
+	tests.StrEq(got.Cron, "0 12 * * FRI", "wrong cron expression")
+
+	tests.Check(DeleteSchedule(db, "lunch"))
+	_, err = GetSchedule(db, "lunch")
+	tests.Exp(err, "schedule should be deleted")
+	tests.Exp(DeleteSchedule(db, "lunch"), "cannot delete a schedule twice")
+}
+
+func TestScheduleRuns(t *testing.T) {
+	tests.InitHelpers(t)
+	db := cmdtest.TempDB()
+	defer db.Destroy()
+
+	due := time.Date(2026, time.October, 23, 12, 0, 0, 0, time.Local)
+	tests.Check(SaveScheduleRun(db, &ScheduleRun{Cart: "lunch", Due: due, Time: due,
+		Status: SchedulePriced, Price: dawg.Cents(2150).In("CAD")}))
+	tests.Check(SaveScheduleRun(db, &ScheduleRun{Cart: "dinner", Due: due, Time: due.Add(time.Minute),
+		Status: ScheduleFailed, Error: "store is closed"}))
+	tests.Check(SaveScheduleRun(db, &ScheduleRun{Cart: "lunch", Due: due.AddDate(0, 0, 7), Time: due.AddDate(0, 0, 7),
+		Status: SchedulePlaced, OrderID: "abc123"}))
+
+	all, err := ScheduleRuns(db, "")
+	tests.Check(err)
+	if len(all) != 3 || all[0].OrderID != "abc123" || all[1].Cart != "dinner" {
+		t.Fatal("runs should be sorted newest first")
+	}
+	lunch, err := ScheduleRuns(db, "lunch")
+	tests.Check(err)
+	if len(lunch) != 2 || lunch[1].Price != dawg.Cents(2150).In("CAD") {
+		t.Errorf("wrong runs for lunch: %+v", lunch)
+	}
+}
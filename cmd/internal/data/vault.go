@@ -0,0 +1,131 @@
+package data
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/harrybrwn/apizza/pkg/cache"
+)
+
+const (
+	vaultBucket = "vault"
+	cardKey     = "card"
+
+	// scrypt parameters recommended for interactive logins
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+	keyLen  = 32
+	saltLen = 16
+)
+
+var (
+	// ErrNoCard is returned when there is no card stored in the database.
+	ErrNoCard = errors.New("no card saved (see 'apizza config card set')")
+
+	// ErrBadPassphrase is returned when the stored card cannot be decrypted.
+	ErrBadPassphrase = errors.New("could not decrypt card: wrong passphrase")
+
+	// additional data authenticated along with the encrypted card
+	cardAD = []byte("apizza card v1")
+)
+
+// Card holds the payment card details that are stored encrypted in the
+// database. The card's security code is never stored.
+type Card struct {
+	Number     string `json:"number"`
+	Expiration string `json:"expiration"`
+	BillingZip string `json:"billing_zip,omitempty"`
+}
+
+// sealed is the format of an encrypted value in the database.
+type sealed struct {
+	N, R, P int
+	Salt    []byte
+	Nonce   []byte
+	Data    []byte
+}
+
+// SaveCard will encrypt the card with a key derived from the passphrase and
+// store it in the database, replacing any card already stored.
+func SaveCard(db *cache.DataBase, card *Card, passphrase []byte) error {
+	if len(passphrase) == 0 {
+		return errors.New("passphrase cannot be empty")
+	}
+	plain, err := json.Marshal(card)
+	if err != nil {
+		return err
+	}
+	s := &sealed{N: scryptN, R: scryptR, P: scryptP, Salt: make([]byte, saltLen)}
+	if _, err = io.ReadFull(rand.Reader, s.Salt); err != nil {
+		return err
+	}
+	aead, err := s.aead(passphrase)
+	if err != nil {
+		return err
+	}
+	s.Nonce = make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, s.Nonce); err != nil {
+		return err
+	}
+	s.Data = aead.Seal(nil, s.Nonce, plain, cardAD)
+
+	raw, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return db.WithBucket(vaultBucket).Put(cardKey, raw)
+}
+
+// LoadCard will decrypt the card stored in the database.
+func LoadCard(db *cache.DataBase, passphrase []byte) (*Card, error) {
+	raw, err := db.WithBucket(vaultBucket).Get(cardKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return nil, ErrNoCard
+	}
+	s := &sealed{}
+	if err = json.Unmarshal(raw, s); err != nil {
+		return nil, err
+	}
+	aead, err := s.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := aead.Open(nil, s.Nonce, s.Data, cardAD)
+	if err != nil {
+		return nil, ErrBadPassphrase
+	}
+	card := &Card{}
+	return card, json.Unmarshal(plain, card)
+}
+
+// HasCard tells whether there is a card stored in the database.
+func HasCard(db *cache.DataBase) bool {
+	return db.WithBucket(vaultBucket).Exists(cardKey)
+}
+
+// DeleteCard removes the stored card from the database.
+func DeleteCard(db *cache.DataBase) error {
+	return db.WithBucket(vaultBucket).Delete(cardKey)
+}
+
+func (s *sealed) aead(passphrase []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, s.Salt, s.N, s.R, s.P, keyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,51 @@
+package data
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestCardVault(t *testing.T) {
+	tests.InitHelpers(t)
+	db := cmdtest.TempDB()
+	defer db.Destroy()
+	pass := []byte("hunter2")
+
+	if HasCard(db) {
+		t.Error("should not have a card yet")
+	}
+	_, err := LoadCard(db, pass)
+	if err != ErrNoCard {
+		t.Errorf("expected ErrNoCard; got %v", err)
+	}
+
+	card := &Card{Number: "4111111111111111", Expiration: "01/30", BillingZip: "20500"}
+	tests.Exp(SaveCard(db, card, nil), "should not save with an empty passphrase")
+	tests.Check(SaveCard(db, card, pass))
+	if !HasCard(db) {
+		t.Error("card should be saved")
+	}
+	raw, err := db.WithBucket(vaultBucket).Get(cardKey)
+	tests.Check(err)
+	if bytes.Contains(raw, []byte(card.Number)) || bytes.Contains(raw, []byte(card.Expiration)) {
+		t.Error("card should not be stored in plaintext")
+	}
+
+	loaded, err := LoadCard(db, pass)
+	tests.Check(err)
+	if *loaded != *card {
+		t.Errorf("wrong card loaded: %+v", loaded)
+	}
+	_, err = LoadCard(db, []byte("hunter3"))
+	if err != ErrBadPassphrase {
+		t.Errorf("expected ErrBadPassphrase; got %v", err)
+	}
+
+	tests.Check(DeleteCard(db))
+	if HasCard(db) {
+		t.Error("card should be deleted")
+	}
+}
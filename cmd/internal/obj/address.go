@@ -10,7 +10,7 @@ import (
 	"github.com/harrybrwn/apizza/dawg"
 )
 
-var _ dawg.Address = (*Address)(nil)
+var _ dawg.DetailedAddress = (*Address)(nil)
 
 // Address represents a street address
 type Address struct {
@@ -18,16 +18,25 @@ type Address struct {
 	CityName string `config:"cityname" json:"cityname"`
 	State    string `config:"state" json:"state"`
 	Zipcode  string `config:"zipcode" json:"zipcode"`
+
+	UnitType             string `config:"unittype" json:"unittype,omitempty"`
+	UnitNumber           string `config:"unitnumber" json:"unitnumber,omitempty"`
+	DeliveryInstructions string `config:"instructions" json:"instructions,omitempty"`
 }
 
 // FromAddress makes an obj.Address from an address interface.
 func FromAddress(a dawg.Address) *Address {
-	return &Address{
+	addr := &Address{
 		Street:   a.LineOne(),
 		CityName: a.City(),
 		State:    a.StateCode(),
 		Zipcode:  a.Zip(),
 	}
+	if details, ok := a.(dawg.DetailedAddress); ok {
+		addr.UnitType, addr.UnitNumber = details.Unit()
+		addr.DeliveryInstructions = details.Instructions()
+	}
+	return addr
 }
 
 // LineOne returns the first line of the address
@@ -50,17 +59,45 @@ func (a *Address) City() string {
 	return a.CityName
 }
 
-// Zip returns the zip code.
+// Zip returns the zip code or postal code. An empty string is returned if
+// it is not valid in any of the dawg markets.
 func (a *Address) Zip() string {
-	if strings.Contains(a.Zipcode, " ") {
-		panic(fmt.Sprintf("bad zipcode %s", a.Zipcode))
-	}
-	if len(a.Zipcode) == 5 {
-		return a.Zipcode
+	for _, m := range dawg.Markets {
+		if code, err := m.PostalCode(a.Zipcode); err == nil {
+			return code
+		}
 	}
 	return ""
 }
 
+// Unit returns the unit type and number of the address.
+func (a *Address) Unit() (unitType, unitNumber string) {
+	return a.UnitType, a.UnitNumber
+}
+
+// Instructions returns the delivery instructions for the address.
+func (a *Address) Instructions() string {
+	return a.DeliveryInstructions
+}
+
+// UnitFmt formats the unit of an address like "Apartment 4B", or "#4B" if
+// the unit has no type. An empty string is returned if the address has no
+// unit.
+func UnitFmt(a dawg.Address) string {
+	details, ok := a.(dawg.DetailedAddress)
+	if !ok {
+		return ""
+	}
+	unitType, number := details.Unit()
+	if number == "" {
+		return ""
+	}
+	if unitType == "" {
+		return "#" + number
+	}
+	return unitType + " " + number
+}
+
 // AddressFmt returns a formatted address string from and Address interface.
 func AddressFmt(a dawg.Address) string {
 	return AddressFmtIndent(a, 0)
@@ -76,8 +113,12 @@ func AddressFmtIndent(a dawg.Address, l int) string {
 		format = "%s\n%s%s, %s %s"
 	}
 
+	lineone := a.LineOne()
+	if unit := UnitFmt(a); unit != "" {
+		lineone += " " + unit
+	}
 	return fmt.Sprintf(format,
-		a.LineOne(),
+		lineone,
 		strings.Repeat(" ", l),
 		a.City(),
 		a.StateCode(),
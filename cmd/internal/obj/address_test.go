@@ -55,3 +55,38 @@ Washington DC, 20500`,
 		t.Error("addr should be empty")
 	}
 }
+
+func TestAddressDetails(t *testing.T) {
+	tests.InitHelpers(t)
+	a := &Address{
+		Street: "1600 Pennsylvania Ave NW", CityName: "Washington", State: "DC", Zipcode: "20500",
+		UnitType: "Suite", UnitNumber: "100", DeliveryInstructions: "ask for the front desk",
+	}
+	tests.StrEq(AddressFmt(a), "1600 Pennsylvania Ave NW Suite 100\nWashington, DC 20500", "wrong address format")
+	a.UnitType = ""
+	tests.StrEq(UnitFmt(a), "#100", "wrong unit format")
+	tests.StrEq(UnitFmt(&dawg.StreetAddr{}), "", "no unit should be empty")
+
+	sa := dawg.StreetAddrFromAddress(a)
+	tests.StrEq(sa.UnitNumber, "100", "unit should be copied to the street address")
+	tests.StrEq(sa.DeliveryInstructions, a.DeliveryInstructions, "instructions should be copied to the street address")
+	back := FromAddress(sa)
+	tests.StrEq(back.UnitNumber, "100", "unit should be copied back")
+	tests.StrEq(back.DeliveryInstructions, a.DeliveryInstructions, "instructions should be copied back")
+
+	raw, err := AsGob(a)
+	tests.Check(err)
+	decoded, err := FromGob(raw)
+	tests.Check(err)
+	tests.StrEq(decoded.DeliveryInstructions, a.DeliveryInstructions, "instructions should be saved")
+}
+
+func TestAddressPostalCodes(t *testing.T) {
+	tests.InitHelpers(t)
+	a := &Address{Street: "100 Queen St W", CityName: "Toronto", State: "on", Zipcode: "m5h2n2"}
+	tests.StrEq(a.Zip(), "M5H 2N2", "canadian postal codes should be formatted")
+	tests.StrEq(a.StateCode(), "ON", "wrong province code")
+	tests.StrEq(AddressFmt(a), "100 Queen St W\nToronto, ON M5H 2N2", "wrong address format")
+	a.Zipcode = "not a zip"
+	tests.StrEq(a.Zip(), "", "bad postal codes should be empty")
+}
@@ -0,0 +1,237 @@
+package out
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/harrybrwn/apizza/dawg"
+)
+
+// MenuExporter writes a menu in some file format.
+type MenuExporter interface {
+	Export(w io.Writer, m *dawg.Menu) error
+}
+
+// MenuExporterFunc is a function that is a MenuExporter.
+type MenuExporterFunc func(io.Writer, *dawg.Menu) error
+
+// Export will call the function.
+func (f MenuExporterFunc) Export(w io.Writer, m *dawg.Menu) error {
+	return f(w, m)
+}
+
+var exporters = map[string]MenuExporter{
+	"csv":      MenuExporterFunc(ExportCSV),
+	"json":     MenuExporterFunc(ExportJSON),
+	"markdown": MenuExporterFunc(ExportMarkdown),
+	"md":       MenuExporterFunc(ExportMarkdown),
+	"html":     MenuExporterFunc(ExportHTML),
+}
+
+// RegisterExporter adds a menu exporter for a format. It will replace any
+// exporter already registered with the same name.
+func RegisterExporter(format string, e MenuExporter) {
+	exporters[strings.ToLower(format)] = e
+}
+
+// GetExporter finds the menu exporter for a format.
+func GetExporter(format string) (MenuExporter, error) {
+	e, ok := exporters[strings.ToLower(format)]
+	if !ok {
+		return nil, fmt.Errorf("cannot export the menu as '%s' (use one of %s)",
+			format, strings.Join(ExportFormats(), ", "))
+	}
+	return e, nil
+}
+
+// ExportFormats returns the names of all the menu export formats.
+func ExportFormats() []string {
+	names := make([]string, 0, len(exporters))
+	for name := range exporters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MenuRow is one variant on the menu along with the product and category
+// that it belongs to. It is what the menu exporters write for each variant.
+type MenuRow struct {
+	Category    string     `json:"category"`
+	ProductCode string     `json:"product_code"`
+	Product     string     `json:"product"`
+	Code        string     `json:"code"`
+	Name        string     `json:"name"`
+	Size        string     `json:"size"`
+	Price       dawg.Money `json:"price"`
+}
+
+// MenuRows lists every variant on the menu in the order that they show up
+// in the menu's categories. Variants in more than one category are only
+// listed once.
+func MenuRows(m *dawg.Menu) []MenuRow {
+	var (
+		rows []MenuRow
+		seen = make(map[string]bool)
+		walk func(dawg.MenuCategory, []string)
+	)
+	walk = func(cat dawg.MenuCategory, path []string) {
+		if cat.Name != "" {
+			path = append(path[:len(path):len(path)], cat.Name)
+		}
+		for _, code := range cat.Products {
+			rows = append(rows, productRows(m, code, strings.Join(path, " / "), seen)...)
+		}
+		for _, sub := range cat.Categories {
+			walk(sub, path)
+		}
+	}
+	walk(m.Categorization.Food, nil)
+	return rows
+}
+
+func productRows(m *dawg.Menu, code, category string, seen map[string]bool) (rows []MenuRow) {
+	p, ok := m.Products[code]
+	if !ok {
+		return nil
+	}
+	for _, vcode := range p.Variants {
+		v, ok := m.Variants[vcode]
+		if !ok || seen[vcode] {
+			continue
+		}
+		seen[vcode] = true
+		rows = append(rows, MenuRow{
+			Category:    category,
+			ProductCode: p.Code,
+			Product:     p.Name,
+			Code:        v.Code,
+			Name:        v.Name,
+			Size:        v.SizeCode,
+			Price:       v.Price,
+		})
+	}
+	return rows
+}
+
+// groupRows splits the rows up by category, keeping the menu's order.
+func groupRows(rows []MenuRow) (groups []rowGroup) {
+	for _, r := range rows {
+		if len(groups) == 0 || groups[len(groups)-1].Category != r.Category {
+			groups = append(groups, rowGroup{Category: r.Category})
+		}
+		last := &groups[len(groups)-1]
+		last.Rows = append(last.Rows, r)
+	}
+	return groups
+}
+
+type rowGroup struct {
+	Category string
+	Rows     []MenuRow
+}
+
+// ExportCSV writes the menu as csv with one row per variant.
+func ExportCSV(w io.Writer, m *dawg.Menu) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"category", "product_code", "product", "code", "name", "size", "price"})
+	for _, r := range MenuRows(m) {
+		cw.Write([]string{r.Category, r.ProductCode, r.Product, r.Code, r.Name, r.Size, decimalStr(r.Price)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ExportJSON writes the menu as a json list of variants.
+func ExportJSON(w io.Writer, m *dawg.Menu) error {
+	rows := MenuRows(m)
+	if rows == nil {
+		rows = []MenuRow{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Store string    `json:"store"`
+		Items []MenuRow `json:"items"`
+	}{Store: m.ID, Items: rows})
+}
+
+// ExportMarkdown writes the menu as a markdown table for each category.
+func ExportMarkdown(w io.Writer, m *dawg.Menu) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Dominos Menu (store %s)\n", m.ID)
+	for _, g := range groupRows(MenuRows(m)) {
+		fmt.Fprintf(&b, "\n## %s\n\n", mdEscape(g.Category))
+		b.WriteString("| Code | Name | Size | Price |\n")
+		b.WriteString("|------|------|------|------:|\n")
+		for _, r := range g.Rows {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+				mdEscape(r.Code), mdEscape(r.Name), mdEscape(r.Size), priceStr(r.Price))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var mdReplacer = strings.NewReplacer("|", "\\|", "\n", " ")
+
+func mdEscape(s string) string {
+	return mdReplacer.Replace(s)
+}
+
+func priceStr(p dawg.Money) string {
+	if p.IsZero() {
+		return ""
+	}
+	return p.String()
+}
+
+func decimalStr(p dawg.Money) string {
+	if p.IsZero() {
+		return ""
+	}
+	return p.Decimal()
+}
+
+// ExportHTML writes the menu as an html page that does not need any other
+// files.
+func ExportHTML(w io.Writer, m *dawg.Menu) error {
+	return menuHTMLTmpl.Execute(w, struct {
+		Store  string
+		Groups []rowGroup
+	}{Store: m.ID, Groups: groupRows(MenuRows(m))})
+}
+
+var menuHTMLTmpl = template.Must(template.New("menu").Funcs(template.FuncMap{
+	"price": priceStr,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Dominos Menu - Store {{ .Store }}</title>
+<style>
+body { font-family: sans-serif; margin: 2em auto; max-width: 50em; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { border-bottom: 1px solid #ddd; padding: 0.3em 0.6em; text-align: left; }
+td.price { text-align: right; }
+</style>
+</head>
+<body>
+<h1>Dominos Menu - Store {{ .Store }}</h1>
+{{- range .Groups }}
+<h2>{{ .Category }}</h2>
+<table>
+<tr><th>Code</th><th>Name</th><th>Size</th><th>Price</th></tr>
+{{- range .Rows }}
+<tr><td>{{ .Code }}</td><td>{{ .Name }}</td><td>{{ .Size }}</td><td class="price">{{ price .Price }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+</body>
+</html>
+`))
@@ -0,0 +1,133 @@
+package out
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func testExportMenu() *dawg.Menu {
+	m := &dawg.Menu{
+		ID: "4336",
+		Products: map[string]*dawg.Product{
+			"S_PIZZA": {
+				ItemCommon: dawg.ItemCommon{Code: "S_PIZZA", Name: "Hand Tossed"},
+				Variants:   []string{"10SCREEN", "12SCREEN"},
+			},
+			"S_BREAD": {
+				ItemCommon: dawg.ItemCommon{Code: "S_BREAD", Name: "Bread | Dips"},
+				Variants:   []string{"B8PCSCB"},
+			},
+		},
+		Variants: map[string]*dawg.Variant{
+			"10SCREEN": {ItemCommon: dawg.ItemCommon{Code: "10SCREEN", Name: `Small (10") Hand Tossed`}, Price: dawg.Cents(799), SizeCode: "10"},
+			"12SCREEN": {ItemCommon: dawg.ItemCommon{Code: "12SCREEN", Name: `Medium (12") Hand Tossed`}, Price: dawg.Cents(999), SizeCode: "12"},
+			"B8PCSCB":  {ItemCommon: dawg.ItemCommon{Code: "B8PCSCB", Name: "Cheesy <Bread>"}, Price: dawg.Cents(699)},
+		},
+	}
+	m.Categorization.Food = dawg.MenuCategory{Categories: []dawg.MenuCategory{
+		{Name: "Pizza", Categories: []dawg.MenuCategory{
+			{Name: "Build Your Own", Products: []string{"S_PIZZA"}},
+		}},
+		{Name: "Bread", Products: []string{"S_BREAD", "S_PIZZA"}},
+	}}
+	return m
+}
+
+func TestExportCSV(t *testing.T) {
+	tests.InitHelpers(t)
+	buf := &bytes.Buffer{}
+	tests.Check(ExportCSV(buf, testExportMenu()))
+	exp := `category,product_code,product,code,name,size,price
+Pizza / Build Your Own,S_PIZZA,Hand Tossed,10SCREEN,"Small (10"") Hand Tossed",10,7.99
+Pizza / Build Your Own,S_PIZZA,Hand Tossed,12SCREEN,"Medium (12"") Hand Tossed",12,9.99
+Bread,S_BREAD,Bread | Dips,B8PCSCB,Cheesy <Bread>,,6.99
+`
+	tests.StrEq(buf.String(), exp, "wrong csv:\n%s", buf.String())
+}
+
+func TestExportJSON(t *testing.T) {
+	tests.InitHelpers(t)
+	buf := &bytes.Buffer{}
+	tests.Check(ExportJSON(buf, testExportMenu()))
+	var res struct {
+		Store string
+		Items []MenuRow
+	}
+	tests.Check(json.Unmarshal(buf.Bytes(), &res))
+	tests.StrEq(res.Store, "4336", "wrong store")
+	if len(res.Items) != 3 {
+		t.Fatalf("got %d items, want 3", len(res.Items))
+	}
+	if res.Items[1] != (MenuRow{"Pizza / Build Your Own", "S_PIZZA", "Hand Tossed", "12SCREEN", `Medium (12") Hand Tossed`, "12", dawg.Cents(999)}) {
+		t.Errorf("wrong item: %+v", res.Items[1])
+	}
+
+	buf.Reset()
+	tests.Check(ExportJSON(buf, &dawg.Menu{}))
+	if !strings.Contains(buf.String(), `"items": []`) {
+		t.Error("an empty menu should have an empty list of items")
+	}
+}
+
+func TestExportMarkdown(t *testing.T) {
+	tests.InitHelpers(t)
+	buf := &bytes.Buffer{}
+	tests.Check(ExportMarkdown(buf, testExportMenu()))
+	exp := "# Dominos Menu (store 4336)\n" +
+		"\n## Pizza / Build Your Own\n\n" +
+		"| Code | Name | Size | Price |\n" +
+		"|------|------|------|------:|\n" +
+		"| 10SCREEN | Small (10\") Hand Tossed | 10 | $7.99 |\n" +
+		"| 12SCREEN | Medium (12\") Hand Tossed | 12 | $9.99 |\n" +
+		"\n## Bread\n\n" +
+		"| Code | Name | Size | Price |\n" +
+		"|------|------|------|------:|\n" +
+		"| B8PCSCB | Cheesy <Bread> |  | $6.99 |\n"
+	tests.StrEq(buf.String(), exp, "wrong markdown:\n%s", buf.String())
+}
+
+func TestExportHTML(t *testing.T) {
+	tests.InitHelpers(t)
+	buf := &bytes.Buffer{}
+	tests.Check(ExportHTML(buf, testExportMenu()))
+	html := buf.String()
+	for _, s := range []string{
+		"<!DOCTYPE html>",
+		"<h2>Pizza / Build Your Own</h2>",
+		`<td>12SCREEN</td><td>Medium (12&#34;) Hand Tossed</td><td>12</td><td class="price">$9.99</td>`,
+		"Cheesy &lt;Bread&gt;",
+	} {
+		if !strings.Contains(html, s) {
+			t.Errorf("html export should have %q", s)
+		}
+	}
+}
+
+func TestGetExporter(t *testing.T) {
+	tests.InitHelpers(t)
+	for _, format := range []string{"csv", "JSON", "md", "markdown", "html"} {
+		_, err := GetExporter(format)
+		tests.Check(err)
+	}
+	_, err := GetExporter("pdf")
+	tests.Exp(err, "should not have a pdf exporter")
+
+	var called bool
+	RegisterExporter("test", MenuExporterFunc(func(w io.Writer, m *dawg.Menu) error {
+		called = true
+		return nil
+	}))
+	defer delete(exporters, "test")
+	e, err := GetExporter("TEST")
+	tests.Check(err)
+	tests.Check(e.Export(nil, nil))
+	if !called {
+		t.Error("registered exporter was not used")
+	}
+}
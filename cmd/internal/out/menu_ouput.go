@@ -2,8 +2,12 @@ package out
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"sort"
 	"strings"
+	"text/tabwriter"
 	"unicode/utf8"
 
 	"github.com/harrybrwn/apizza/dawg"
@@ -12,21 +16,282 @@ import (
 // PrintMenu is the function that prints out a menu category and any of its
 // sub-categories.
 func PrintMenu(cat dawg.MenuCategory, depth int, m *dawg.Menu) error {
+	return PrintMenuOpts(cat, depth, m, MenuOptions{})
+}
+
+// MenuOptions changes how the menu is printed.
+type MenuOptions struct {
+	// SortBy is either "price" or "name". Items are printed in the same
+	// order as the menu if it is empty.
+	SortBy string
+
+	// MaxPrice hides the items that cost more than it. Zero means there is
+	// no limit.
+	MaxPrice dawg.Money
+
+	// Vegetarian and GlutenFree only show the items that are vegetarian or
+	// gluten free. MaxCalories hides the items with more calories per serving
+	// than it and zero means there is no limit. Items without any nutrition
+	// information are hidden when any of these are set.
+	Vegetarian  bool
+	GlutenFree  bool
+	MaxCalories int
+}
+
+// menu sorting options
+const (
+	SortByPrice = "price"
+	SortByName  = "name"
+)
+
+// Valid returns an error if the options cannot be used.
+func (o MenuOptions) Valid() error {
+	switch o.SortBy {
+	case "", SortByPrice, SortByName:
+	default:
+		return fmt.Errorf("cannot sort the menu by '%s' (use %s or %s)", o.SortBy, SortByPrice, SortByName)
+	}
+	if o.MaxPrice.Units < 0 {
+		return errors.New("the max price cannot be negative")
+	}
+	if o.MaxCalories < 0 {
+		return errors.New("the max calories cannot be negative")
+	}
+	return nil
+}
+
+func (o MenuOptions) filtersNutrition() bool {
+	return o.Vegetarian || o.GlutenFree || o.MaxCalories > 0
+}
+
+// keep tells whether a row passes the filters.
+func (o MenuOptions) keep(r menuRow) bool {
+	if !o.MaxPrice.IsZero() && (!r.hasPrice() || r.price.Units > o.MaxPrice.Units) {
+		return false
+	}
+	if !o.filtersNutrition() {
+		return true
+	}
+	n, ok := r.item.Nutrition()
+	if !ok {
+		return false
+	}
+	return (!o.Vegetarian || n.Vegetarian) &&
+		(!o.GlutenFree || n.GlutenFree) &&
+		(o.MaxCalories == 0 || n.Calories <= o.MaxCalories)
+}
+
+// PrintMenuOpts prints a menu category and its sub-categories with the price,
+// size, and flavor of each item. Categories with nothing left to show after
+// filtering are left out.
+func PrintMenuOpts(cat dawg.MenuCategory, depth int, m *dawg.Menu, opts MenuOptions) error {
+	if err := opts.Valid(); err != nil {
+		return err
+	}
+	_, err := output.Write(renderCategory(cat, depth, m, opts))
+	return err
+}
+
+func renderCategory(cat dawg.MenuCategory, depth int, m *dawg.Menu, opts MenuOptions) []byte {
 	if cat.IsEmpty() {
 		return nil
 	}
-	fmt.Fprintf(output, "\n%s%s%s%s\n", spaces(depth*2), strings.Repeat("-", 8),
-		cat.Name, strings.Repeat("-", 60-len(cat.Name)-(depth*2)))
+	body := &bytes.Buffer{}
 	if cat.HasItems() {
-		for _, p := range cat.Products {
-			printCategory(p, depth+1, m)
+		// one tabwriter for the whole category so that every product's rows
+		// line up with each other
+		tw := tabwriter.NewWriter(body, 0, 4, 2, ' ', 0)
+		for _, p := range menuProducts(cat.Products, m, opts) {
+			p.write(tw, depth+1)
 		}
+		tw.Flush()
+		trimLines(body)
 	} else {
 		for _, category := range cat.Categories {
-			PrintMenu(category, depth+1, m)
+			body.Write(renderCategory(category, depth+1, m, opts))
 		}
 	}
-	return nil
+	if body.Len() == 0 {
+		return nil
+	}
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "\n%s%s%s%s\n", spaces(depth*2), strings.Repeat("-", 8),
+		cat.Name, strings.Repeat("-", 60-len(cat.Name)-(depth*2)))
+	body.WriteTo(buf)
+	return buf.Bytes()
+}
+
+// menuProduct is a product, or a preconfigured product, and the variants of
+// it that are going to be printed.
+type menuProduct struct {
+	code, name string
+	single     bool // only has one variant, printed on one line
+	rows       []menuRow
+}
+
+type menuRow struct {
+	code, name   string
+	size, flavor string
+	price        dawg.Money
+	item         nutritional
+}
+
+type nutritional interface {
+	Nutrition() (dawg.Nutrition, bool)
+}
+
+func newMenuRow(item nutritional, code, name, size, flavor string, price dawg.Money) menuRow {
+	return menuRow{code: code, name: name, size: size, flavor: flavor, price: price, item: item}
+}
+
+func (r menuRow) hasPrice() bool {
+	return !r.price.IsZero()
+}
+
+func (r menuRow) priceStr() string {
+	if !r.hasPrice() {
+		return ""
+	}
+	return r.price.String()
+}
+
+// lowest returns the lowest price of the product.
+func (p *menuProduct) lowest() (dawg.Money, bool) {
+	var (
+		min dawg.Money
+		ok  bool
+	)
+	for _, r := range p.rows {
+		if r.hasPrice() && (!ok || r.price.Units < min.Units) {
+			min, ok = r.price, true
+		}
+	}
+	return min, ok
+}
+
+// write writes the product's rows to a tabwriter. The line for a product with
+// more than one variant has the same columns as the rows because a line
+// without them would split the category into separately aligned tables.
+func (p *menuProduct) write(tw io.Writer, indent int) {
+	if p.single {
+		r := p.rows[0]
+		fmt.Fprintf(tw, "%s%s\t%s\t%s\t%s\t%s\n", spaces(indent*2),
+			r.code, r.name, r.size, r.flavor, r.priceStr())
+		return
+	}
+	fmt.Fprintf(tw, "%s%s\t%s\t\t\t\n", spaces(indent*2), p.code, p.name)
+	for _, r := range p.rows {
+		fmt.Fprintf(tw, "%s%s\t%s\t%s\t%s\t%s\n", spaces(2*(indent+1)),
+			r.code, r.name, r.size, r.flavor, r.priceStr())
+	}
+}
+
+// trimLines removes the padding that the tabwriter leaves at the end of lines
+// with empty columns.
+func trimLines(buf *bytes.Buffer) {
+	lines := bytes.SplitAfter(buf.Bytes(), []byte{'\n'})
+	trimmed := make([]byte, 0, buf.Len())
+	for _, l := range lines {
+		trimmed = append(trimmed, bytes.TrimRight(l, " \n")...)
+		if bytes.HasSuffix(l, []byte{'\n'}) {
+			trimmed = append(trimmed, '\n')
+		}
+	}
+	buf.Reset()
+	buf.Write(trimmed)
+}
+
+// menuProducts finds the products for a list of codes, leaving out the
+// variants that do not pass the filters and sorting them.
+func menuProducts(codes []string, m *dawg.Menu, opts MenuOptions) []*menuProduct {
+	var products []*menuProduct
+	for _, code := range codes {
+		var p *menuProduct
+		switch item := m.FindItem(code).(type) {
+		case *dawg.Product:
+			p = &menuProduct{code: item.Code, name: item.Name, single: len(item.Variants) == 1}
+			for _, vcode := range item.Variants {
+				v, err := m.GetVariant(vcode)
+				if err != nil {
+					continue
+				}
+				p.rows = append(p.rows, newMenuRow(v, vcode, v.Name, sizeName(v), flavorName(v), v.Price))
+			}
+		case *dawg.PreConfiguredProduct:
+			p = &menuProduct{code: item.Code, name: item.Name, single: true}
+			var price dawg.Money
+			if v, ok := m.Variants[item.Code]; ok {
+				price = v.Price
+			}
+			p.rows = []menuRow{newMenuRow(item, item.Code, item.Name, item.Size, "", price)}
+		default:
+			continue
+		}
+
+		rows := p.rows[:0]
+		for _, r := range p.rows {
+			if opts.keep(r) {
+				rows = append(rows, r)
+			}
+		}
+		p.rows = rows
+		if len(p.rows) == 0 {
+			continue
+		}
+		sortRows(p.rows, opts.SortBy)
+		products = append(products, p)
+	}
+
+	switch opts.SortBy {
+	case SortByPrice:
+		sort.SliceStable(products, func(i, j int) bool {
+			a, aok := products[i].lowest()
+			b, bok := products[j].lowest()
+			if aok != bok {
+				return aok
+			}
+			return a.Units < b.Units
+		})
+	case SortByName:
+		sort.SliceStable(products, func(i, j int) bool {
+			return products[i].name < products[j].name
+		})
+	}
+	return products
+}
+
+// sizeName is the name of a variant's size or the size code if the menu does
+// not have it.
+func sizeName(v *dawg.Variant) string {
+	if s, ok := v.Size(); ok && s.Name != "" {
+		return s.Name
+	}
+	return v.SizeCode
+}
+
+// flavorName is the name of a variant's flavor or the flavor code if the menu
+// does not have it.
+func flavorName(v *dawg.Variant) string {
+	if f, ok := v.Flavor(); ok && f.Name != "" {
+		return f.Name
+	}
+	return v.FlavorCode
+}
+
+func sortRows(rows []menuRow, by string) {
+	switch by {
+	case SortByPrice:
+		sort.SliceStable(rows, func(i, j int) bool {
+			if rows[i].hasPrice() != rows[j].hasPrice() {
+				return rows[i].hasPrice()
+			}
+			return rows[i].price.Units < rows[j].price.Units
+		})
+	case SortByName:
+		sort.SliceStable(rows, func(i, j int) bool {
+			return rows[i].name < rows[j].name
+		})
+	}
 }
 
 // ItemInfo prints the common information for an Item.
@@ -43,10 +308,12 @@ func ItemInfo(prod dawg.Item, menu *dawg.Menu) error {
 			break
 		}
 		fmt.Fprintf(o, "  Parent Product: '%s' [%s]\n", parent.ItemName(), parent.ItemCode())
+		printNutrition(o, p)
 
 	case *dawg.PreConfiguredProduct:
 		fmt.Fprintf(o, "  Description: '%s'\n", FormatLineIndent(p.Description, 70, 16))
 		fmt.Fprintf(o, "  Size: %s\n", p.Size)
+		printNutrition(o, p)
 
 	case *dawg.Product:
 		PrintProduct(p)
@@ -55,6 +322,33 @@ func ItemInfo(prod dawg.Item, menu *dawg.Menu) error {
 	return err
 }
 
+func printNutrition(w io.Writer, item nutritional) {
+	n, ok := item.Nutrition()
+	if !ok {
+		return
+	}
+	if n.Servings > 1 {
+		fmt.Fprintf(w, "  Calories: %d per serving (%d servings)\n", n.Calories, n.Servings)
+	} else {
+		fmt.Fprintf(w, "  Calories: %d\n", n.Calories)
+	}
+	allergens := "none listed"
+	if len(n.Allergens) > 0 {
+		allergens = strings.Join(n.Allergens, ", ")
+	}
+	fmt.Fprintf(w, "  Allergens: %s\n", allergens)
+	fmt.Fprintf(w, "  Vegetarian: %s\n", YesNo(n.Vegetarian))
+	fmt.Fprintf(w, "  Gluten Free: %s\n", YesNo(n.GlutenFree))
+}
+
+// YesNo returns "yes" for true and "no" for false.
+func YesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
 func iteminfo(i dawg.Item, menu *dawg.Menu) {
 	fmt.Fprintf(output, "%s\n", i.ItemName())
 	fmt.Fprintf(output, "  Code: %s\n", i.ItemCode())
@@ -70,39 +364,6 @@ func iteminfo(i dawg.Item, menu *dawg.Menu) {
 	}
 }
 
-func printCategory(code string, indent int, m *dawg.Menu) {
-	item := m.FindItem(code)
-
-	switch product := item.(type) {
-	case *dawg.Product:
-		if len(product.Variants) == 1 {
-			p, err := m.GetVariant(product.Variants[0])
-			if err != nil {
-				panic(err)
-			}
-
-			fmt.Fprintf(output, "%s%s  %s\n", spaces(indent*2), p.Code, p.Name)
-			return
-		}
-
-		fmt.Fprintf(output, "%s%s [%s]\n", spaces(indent*2), item.ItemName(), item.ItemCode())
-		n := maxStrLen(product.Variants)
-		for _, variant := range product.Variants {
-			v, err := m.GetVariant(variant)
-			if err != nil {
-				continue
-			}
-			fmt.Fprintf(output, "%s%s %s %s\n",
-				spaces(2*(indent+1)), variant,
-				spaces(n-strLen(variant)), v.Name)
-		}
-
-	case *dawg.PreConfiguredProduct:
-		fmt.Fprintf(output, "%s%s   %s\n", spaces(indent*2),
-			item.ItemCode(), item.ItemName())
-	}
-}
-
 func maxStrLen(list []string) int {
 	max := 0
 	for _, s := range list {
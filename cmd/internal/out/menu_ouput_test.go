@@ -0,0 +1,110 @@
+package out
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestPrintMenuOpts(t *testing.T) {
+	tests.InitHelpers(t)
+	m := testExportMenu()
+	m.Variants["10SCREEN"].FlavorCode = "HANDTOSS"
+	m.Variants["12SCREEN"].FlavorCode = "HANDTOSS"
+	m.Products["S_PIZZA"].ProductType = "Pizza"
+	for _, v := range m.Variants {
+		v.ProductCode = "S_PIZZA"
+	}
+	m.Variants["B8PCSCB"].ProductCode = "S_BREAD"
+	m.Sizes = map[string]map[string]dawg.Size{"Pizza": {"10": {Code: "10", Name: `Small (10")`}}}
+	m.Flavors = map[string]map[string]dawg.Flavor{"Pizza": {"HANDTOSS": {Code: "HANDTOSS", Name: "Hand Tossed"}}}
+	buf := &bytes.Buffer{}
+	SetOutput(buf)
+	defer ResetOutput()
+
+	print := func(opts MenuOptions) string {
+		buf.Reset()
+		for _, cat := range m.Categorization.Food.Categories {
+			tests.Check(PrintMenuOpts(cat, 0, m, opts))
+		}
+		return buf.String()
+	}
+
+	res := print(MenuOptions{})
+	for _, s := range []string{
+		"  S_PIZZA     Hand Tossed\n",
+		`    10SCREEN  Small (10") Hand Tossed   Small (10")  Hand Tossed  $7.99`,
+		`    12SCREEN  Medium (12") Hand Tossed  12           Hand Tossed  $9.99`,
+	} {
+		if !strings.Contains(res, s) {
+			t.Errorf("menu should contain %q:\n%s", s, res)
+		}
+	}
+
+	// the rows of every product in a category line up
+	bread := strings.Index(res, "\n--------Bread")
+	for _, s := range []string{
+		"  B8PCSCB     Cheesy <Bread>                                      $6.99\n",
+		"  S_PIZZA     Hand Tossed\n",
+		`    10SCREEN  Small (10") Hand Tossed   Small (10")  Hand Tossed  $7.99`,
+	} {
+		if !strings.Contains(res[bread:], s) {
+			t.Errorf("bread category should contain %q:\n%s", s, res[bread:])
+		}
+	}
+
+	m.Categorization.Food.Categories[1].Products = []string{"S_PIZZA", "S_BREAD"}
+	res = print(MenuOptions{SortBy: SortByPrice})
+	bread = strings.Index(res, "\n--------Bread")
+	if strings.Index(res[bread:], "B8PCSCB") > strings.Index(res[bread:], "S_PIZZA") {
+		t.Errorf("cheaper items should be first:\n%s", res)
+	}
+	res = print(MenuOptions{SortBy: SortByName})
+	if strings.Index(res, "Medium") > strings.Index(res, "Small") {
+		t.Errorf("variants should be sorted by name:\n%s", res)
+	}
+
+	res = print(MenuOptions{MaxPrice: dawg.Cents(700)})
+	if strings.Contains(res, "Pizza") || strings.Contains(res, "SCREEN") {
+		t.Errorf("items over the max price should not be printed:\n%s", res)
+	}
+	if !strings.Contains(res, "B8PCSCB") {
+		t.Errorf("items under the max price should be printed:\n%s", res)
+	}
+
+	res = print(MenuOptions{GlutenFree: true})
+	if res != "" {
+		t.Errorf("nothing on the menu is gluten free:\n%s", res)
+	}
+	res = print(MenuOptions{Vegetarian: true, MaxCalories: 190})
+	if !strings.Contains(res, "10SCREEN") || !strings.Contains(res, "B8PCSCB") || strings.Contains(res, "12SCREEN") {
+		t.Errorf("wrong items for 190 calories:\n%s", res)
+	}
+
+	tests.Exp(PrintMenuOpts(dawg.MenuCategory{}, 0, m, MenuOptions{SortBy: "calories"}))
+	tests.Exp(PrintMenuOpts(dawg.MenuCategory{}, 0, m, MenuOptions{MaxCalories: -1}))
+	tests.Exp(PrintMenuOpts(dawg.MenuCategory{}, 0, m, MenuOptions{MaxPrice: dawg.Cents(-100)}))
+}
+
+func TestPrintOrderNutrition(t *testing.T) {
+	tests.InitHelpers(t)
+	buf := &bytes.Buffer{}
+	SetOutput(buf)
+	defer ResetOutput()
+	tests.Check(PrintOrderNutrition(&dawg.OrderNutrition{
+		Calories:   1600,
+		Allergens:  []string{"milk", "wheat"},
+		Vegetarian: true,
+		Unknown:    []string{"W08PHOTW"},
+	}))
+	tests.Compare(t, buf.String(), `  nutrition:
+    calories:    1600
+    allergens:   milk, wheat
+    vegetarian:  yes
+    gluten free: no
+    unknown:     W08PHOTW (missing information)
+`)
+}
@@ -88,7 +88,7 @@ func lineone(str string, start, length int) (string, int) {
 func PrintOrder(o *dawg.Order, full, price bool) (err error) {
 	var (
 		t      string
-		oPrice float64
+		oPrice dawg.Money
 	)
 
 	if full {
@@ -99,16 +99,34 @@ func PrintOrder(o *dawg.Order, full, price bool) (err error) {
 	if price {
 		oPrice, err = o.Price()
 	}
+	return errs.Pair(err, printOrder(o, t, oPrice, false))
+}
+
+// PrintOrderNutrition prints the nutrition information for an order.
+func PrintOrderNutrition(n *dawg.OrderNutrition) error {
+	return tmpl(output, orderNutritionTmpl, n)
+}
+
+// PrintOrderEstimate prints the full order with an estimated price.
+func PrintOrderEstimate(o *dawg.Order, price dawg.Money) error {
+	return printOrder(o, defaultOrderTmpl, price, true)
+}
+
+func printOrder(o *dawg.Order, t string, price dawg.Money, estimate bool) error {
 	data := struct {
 		*dawg.Order
-		Addr  string
-		Price float64
+		Addr     string
+		Price    dawg.Money
+		Estimate bool
+		Points   int
 	}{
-		Order: o,
-		Addr:  obj.AddressFmtIndent(o.Address, 11),
-		Price: oPrice,
+		Order:    o,
+		Addr:     obj.AddressFmtIndent(o.Address, 11),
+		Price:    price,
+		Estimate: estimate,
+		Points:   o.PointsRedeemed(),
 	}
-	return errs.Pair(err, tmpl(output, t, data))
+	return tmpl(output, t, data)
 }
 
 // PrintVariant will display a dawg.Variant in a pretty way.
@@ -99,8 +99,12 @@ func TestPrintItems(t *testing.T) {
   Toppings:
     Robust Inspired Tomato Sauce (X): full 1
     Cheese (C): full 1
-  Price: 13.99
+  Price: $13.99
   Parent Product: 'Pizza' [S_PIZZA]
+  Calories: 290 per serving (8 servings)
+  Allergens: milk, wheat, soy
+  Vegetarian: yes
+  Gluten Free: no
 `
 	// we are not testing for the output of the toppings section
 	// because the order of the toppings relies on a map and we cannot guarantee
@@ -2,17 +2,23 @@ package out
 
 import (
 	"io"
+	"strings"
 	"text/template"
 
 	"github.com/harrybrwn/apizza/pkg/errs"
 )
 
 func tmpl(w io.Writer, tmplt string, a interface{}) (err error) {
-	t := template.New("apizza")
+	t := template.New("apizza").Funcs(tmplFuncs)
 	t, err = t.Parse(tmplt)
 	return errs.Pair(err, t.Execute(w, a))
 }
 
+var tmplFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"yesno": YesNo,
+}
+
 var defaultOrderTmpl = `{{ .OrderName }}
   products:{{ range .Products }}
     {{.Name}}
@@ -23,9 +29,21 @@ var defaultOrderTmpl = `{{ .OrderName }}
   storeID: {{.StoreID}}
   method:  {{.ServiceMethod}}
   address: {{.Addr -}}
-{{ if .Price }}
-  price:   ${{ .Price -}}
-{{else}}{{end}}
+{{ if not .Price.IsZero }}
+  price:   {{ .Price }}{{ if .Estimate }} (estimate, before taxes and fees){{end -}}
+{{else}}{{end}}{{ if .Points }}
+  points:  {{ .Points -}}
+{{end}}
+`
+
+var orderNutritionTmpl = `  nutrition:
+    calories:    {{ .Calories }}
+    allergens:   {{ if .Allergens }}{{ join .Allergens ", " }}{{ else }}none listed{{ end }}
+    vegetarian:  {{ yesno .Vegetarian }}
+    gluten free: {{ yesno .GlutenFree }}
+{{- if .Unknown }}
+    unknown:     {{ join .Unknown ", " }} (missing information)
+{{- end }}
 `
 
 var cartOrderTmpl = `  {{ .OrderName }} - {{ range .Products }} {{.Code}}, {{end}}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// Redactable is a value that holds sensitive data that should never be
+// written to the logs as is.
+type Redactable interface {
+	// Redacted returns a string version of the value with any sensitive
+	// data hidden.
+	Redacted() string
+}
+
+// StructuredLogger writes key=value log lines in the style of the log/slog
+// text handler. Any value that implements Redactable is replaced by its
+// redacted form before being written.
+type StructuredLogger struct {
+	// Logger is where the log lines are written. The standard logger is used
+	// if it is nil so that log.SetOutput is respected.
+	Logger *log.Logger
+}
+
+var logger = &StructuredLogger{}
+
+// Info logs a message with key value pairs at the info level.
+func (l *StructuredLogger) Info(msg string, args ...interface{}) {
+	l.log("INFO", msg, args)
+}
+
+// Warn logs a message with key value pairs at the warning level.
+func (l *StructuredLogger) Warn(msg string, args ...interface{}) {
+	l.log("WARN", msg, args)
+}
+
+// Error logs a message with key value pairs at the error level.
+func (l *StructuredLogger) Error(msg string, args ...interface{}) {
+	l.log("ERROR", msg, args)
+}
+
+func (l *StructuredLogger) log(level, msg string, args []interface{}) {
+	var b strings.Builder
+	b.WriteString("level=")
+	b.WriteString(level)
+	b.WriteString(" msg=")
+	b.WriteString(logValue(msg))
+
+	for i := 0; i < len(args); i += 2 {
+		key, ok := args[i].(string)
+		if !ok || i+1 == len(args) {
+			// same as log/slog for arguments that are not key value pairs
+			key, i = "!BADKEY", i-1
+		}
+		b.WriteByte(' ')
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(logValue(args[i+1]))
+	}
+
+	if l.Logger == nil {
+		log.Output(3, b.String())
+	} else {
+		l.Logger.Output(3, b.String())
+	}
+}
+
+func logValue(v interface{}) string {
+	var s string
+	switch val := v.(type) {
+	case Redactable:
+		s = val.Redacted()
+	case error:
+		s = val.Error()
+	case string:
+		s = val
+	default:
+		s = fmt.Sprint(val)
+	}
+	if json.Valid([]byte(s)) && (strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[")) {
+		// json objects are kept as is so they stay readable
+		return s
+	}
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
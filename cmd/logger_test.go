@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/harrybrwn/apizza/dawg"
+)
+
+func TestStructuredLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := &StructuredLogger{Logger: log.New(buf, "", 0)}
+
+	l.Info("sending order", "name", "my order", "count", 2, "err", errors.New("oops"), 5)
+	exp := "level=INFO msg=\"sending order\" name=\"my order\" count=2 err=oops !BADKEY=5\n"
+	if buf.String() != exp {
+		t.Errorf("got:\n%q\nwant:\n%q", buf.String(), exp)
+	}
+	buf.Reset()
+
+	o := &dawg.Order{Phone: "2025550123", Email: "bob@example.com"}
+	o.AddCard(dawg.NewCard("4111111111111111", "01/30", 123))
+	l.Error("failed", "order", o)
+	s := buf.String()
+	if !strings.HasPrefix(s, "level=ERROR msg=failed order={\"Order\":") {
+		t.Errorf("orders should be logged as json: %s", s)
+	}
+	for _, secret := range []string{"4111111111111111", "2025550123", "bob@example.com", "\"123\""} {
+		if strings.Contains(s, secret) {
+			t.Errorf("log output should not contain %s", secret)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	fp "path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/harrybrwn/apizza/cmd/cli"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/config"
+)
+
+// `apizza logs`
+type logsCmd struct {
+	cli.CliCommand
+	dir func() string
+}
+
+// NewLogsCmd creates the 'logs' command.
+func NewLogsCmd(b cli.Builder) cli.CliCommand {
+	c := &logsCmd{
+		dir: func() string { return fp.Join(config.Folder(), "logs") },
+	}
+	c.CliCommand = b.Build("logs", "Manage the apizza log files", c)
+	c.Addcmd(b.Build("scrub",
+		"Hide card details, phone numbers, and emails in existing logs",
+		cli.RunFunction(c.scrub)))
+	return c
+}
+
+// Run will list the log files.
+func (c *logsCmd) Run(cmd *cobra.Command, args []string) error {
+	files, err := logFiles(c.dir())
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		c.Println(f)
+	}
+	return nil
+}
+
+func (c *logsCmd) scrub(cmd *cobra.Command, args []string) error {
+	files, err := logFiles(c.dir())
+	if err != nil {
+		return err
+	}
+	var n int
+	for _, f := range files {
+		changed, err := scrubLogFile(f)
+		if err != nil {
+			return err
+		}
+		if changed {
+			n++
+		}
+	}
+	c.Printf("scrubbed %d of %d log files\n", n, len(files))
+	return nil
+}
+
+func logFiles(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(infos))
+	for _, info := range infos {
+		if info.IsDir() || strings.HasSuffix(info.Name(), ".gz") {
+			continue
+		}
+		files = append(files, fp.Join(dir, info.Name()))
+	}
+	return files, nil
+}
+
+// matches the sensitive json fields of an order. The quotes are allowed
+// to be escaped so that orders logged inside of a quoted string are
+// scrubbed too.
+var sensitiveLogField = regexp.MustCompile(
+	`(\\?"(Number|Expiration|SecurityCode|Phone|Email)\\?"\s*:\s*\\?")([^"\\]*)`)
+
+func scrubLog(b []byte) []byte {
+	return sensitiveLogField.ReplaceAllFunc(b, func(match []byte) []byte {
+		parts := sensitiveLogField.FindSubmatch(match)
+		val := string(parts[3])
+		if val == "" {
+			return match
+		}
+		switch string(parts[2]) {
+		case "Number":
+			val = dawg.MaskCardNumber(val)
+		case "Expiration":
+			val = "****"
+		case "SecurityCode":
+			val = "***"
+		case "Phone":
+			val = dawg.MaskPhone(val)
+		case "Email":
+			val = dawg.MaskEmail(val)
+		}
+		return append(append([]byte{}, parts[1]...), val...)
+	})
+}
+
+// scrubLogFile rewrites a log file with all the sensitive data hidden. The
+// file is only rewritten if something changed.
+func scrubLogFile(name string) (bool, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return false, err
+	}
+	raw, err := ioutil.ReadFile(name)
+	if err != nil {
+		return false, err
+	}
+	scrubbed := scrubLog(raw)
+	if string(scrubbed) == string(raw) {
+		return false, nil
+	}
+
+	tmp, err := ioutil.TempFile(fp.Dir(name), ".scrub-")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp.Name()) // fails quietly after the rename
+	if _, err = tmp.Write(scrubbed); err != nil {
+		tmp.Close()
+		return false, err
+	}
+	if err = tmp.Close(); err != nil {
+		return false, err
+	}
+	if err = os.Chmod(tmp.Name(), info.Mode()); err != nil {
+		return false, err
+	}
+	return true, os.Rename(tmp.Name(), name)
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	fp "path/filepath"
+	"testing"
+
+	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestScrubLog(t *testing.T) {
+	in := `2020/04/01 12:00:00 sending order: {
+    "Order": {
+        "Email": "bob@example.com",
+        "Phone": "2025550123",
+        "Address": {"StreetNumber": "1600"},
+        "Payments": [{"Number": "4111111111111111", "Expiration": "0130", "SecurityCode": "123"}]
+    }
+}
+2020/04/02 12:00:00 msg="{\"Phone\":\"2025550123\",\"Email\":\"\"}"
+`
+	exp := `2020/04/01 12:00:00 sending order: {
+    "Order": {
+        "Email": "b**@example.com",
+        "Phone": "******0123",
+        "Address": {"StreetNumber": "1600"},
+        "Payments": [{"Number": "************1111", "Expiration": "****", "SecurityCode": "***"}]
+    }
+}
+2020/04/02 12:00:00 msg="{\"Phone\":\"******0123\",\"Email\":\"\"}"
+`
+	res := string(scrubLog([]byte(in)))
+	if res != exp {
+		t.Errorf("got:\n%s\nwant:\n%s", res, exp)
+	}
+	if string(scrubLog([]byte(exp))) != exp {
+		t.Error("scrubbing should not change scrubbed logs")
+	}
+}
+
+func TestLogsCmd(t *testing.T) {
+	tests.InitHelpers(t)
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	dir, err := ioutil.TempDir("", "apizza-logs")
+	tests.Fatal(err)
+	defer os.RemoveAll(dir)
+
+	dirty := fp.Join(dir, "dev.log")
+	clean := fp.Join(dir, "other.log")
+	tests.Check(ioutil.WriteFile(dirty, []byte(`{"SecurityCode": "123"}`), 0600))
+	tests.Check(ioutil.WriteFile(clean, []byte("nothing to see here"), 0644))
+
+	c := NewLogsCmd(r).(*logsCmd)
+	c.dir = func() string { return dir }
+	tests.Check(c.scrub(c.Cmd(), nil))
+	r.Compare(t, "scrubbed 1 of 2 log files\n")
+
+	b, err := ioutil.ReadFile(dirty)
+	tests.Check(err)
+	tests.StrEq(string(b), `{"SecurityCode": "***"}`, "log file was not scrubbed")
+	info, err := os.Stat(dirty)
+	tests.Check(err)
+	if info.Mode().Perm() != 0600 {
+		t.Error("scrubbing should keep the file mode")
+	}
+	files, err := logFiles(dir)
+	tests.Check(err)
+	if len(files) != 2 {
+		t.Error("temporary files should be cleaned up")
+	}
+}
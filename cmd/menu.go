@@ -15,9 +15,12 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
+	"path"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -52,10 +55,35 @@ type menuCmd struct {
 	showCategories bool
 	item           string
 	category       string
+
+	searchLimit int
+	exact       bool
+	changes     bool
+
+	export string
+	output string
+
+	sortBy      string
+	maxPrice    string
+	vegetarian  bool
+	glutenFree  bool
+	maxCalories int
 }
 
 func (c *menuCmd) Run(cmd *cobra.Command, args []string) error {
-	if err := c.db.UpdateTS("menu", c); err != nil {
+	if err := c.UpdateMenu(); err != nil {
+		return err
+	}
+	if d := c.Changes(); c.changes && d != nil && !d.Empty() {
+		c.Println("The menu has changed since it was last updated.")
+		if err := printMenuDiff(c.Output(), d); err != nil {
+			return err
+		}
+	}
+	if c.export != "" {
+		return c.exportMenu()
+	}
+	if _, err := c.menuOptions(); err != nil {
 		return err
 	}
 	out.SetOutput(c.Output())
@@ -119,7 +147,8 @@ func NewMenuCmd(b cli.Builder) cli.CliCommand {
 
 To show a subdivision of the menu, give an item or
 category to the --category and --item flags or give them
-as an argument to the command itself.`
+as an argument to the command itself. Categories can be
+nested paths of names or codes, like 'pizza/specialty'.`
 
 	flags := c.Flags()
 	flags.BoolVarP(&c.all, "all", "a", c.all, "show the entire menu")
@@ -133,13 +162,158 @@ as an argument to the command itself.`
 	flags.BoolVarP(&c.preconfigured, "preconfigured",
 		"p", c.preconfigured, "show the pre-configured products on the dominos menu")
 	flags.BoolVar(&c.showCategories, "show-categories", c.showCategories, "print categories")
+	flags.BoolVar(&c.changes, "changes", false, "print the changes to the menu if it gets updated")
+	flags.StringVar(&c.export, "export", "", fmt.Sprintf("export the menu as %s", strings.Join(out.ExportFormats(), ", ")))
+	flags.StringVarP(&c.output, "output", "o", "", "the file that --export writes to (defaults to stdout)")
+	flags.StringVar(&c.sortBy, "sort", "", "sort the menu by price or name")
+	flags.StringVar(&c.maxPrice, "max-price", "", "only show items that cost less than this")
+	flags.BoolVar(&c.vegetarian, "vegetarian", false, "only show vegetarian items")
+	flags.BoolVar(&c.glutenFree, "gluten-free", false, "only show gluten free items")
+	flags.IntVar(&c.maxCalories, "max-calories", 0, "only show items with fewer calories per serving than this")
+
+	search := b.Build("search <query>", "Search the menu", cli.RunFunction(c.search))
+	search.Cmd().Long = `Search the menu for items by name, description, or tags.
+
+Small typos in the search are allowed unless --exact is given.`
+	search.Flags().IntVarP(&c.searchLimit, "limit", "n", 15, "the most results to show (0 shows all of them)")
+	search.Flags().BoolVar(&c.exact, "exact", false, "do not allow typos in the search")
+	c.Addcmd(
+		search,
+		b.Build("diff", "Show the changes since the last menu update", cli.RunFunction(c.diff)),
+	)
 	return c
 }
 
+func (c *menuCmd) diff(cmd *cobra.Command, args []string) error {
+	if err := c.UpdateMenu(); err != nil {
+		return err
+	}
+	prev, err := c.PreviousMenu()
+	if err != nil {
+		return err
+	}
+	if prev == nil {
+		c.Printf("No older menu has been saved for store %s.\n", c.Menu().ID)
+		return nil
+	}
+	return printMenuDiff(c.Output(), dawg.DiffMenus(prev, c.Menu()))
+}
+
+func printMenuDiff(w io.Writer, d *dawg.MenuDiff) error {
+	if d.Empty() {
+		_, err := fmt.Fprintln(w, "The menu has not changed.")
+		return err
+	}
+	tw := newTabWriter(w)
+	if len(d.PriceChanges) > 0 {
+		fmt.Fprintln(tw, "Price changes:")
+		for _, p := range d.PriceChanges {
+			fmt.Fprintf(tw, "  %s\t%s\t%s -> %s\n", p.Code, p.Name, p.OldPrice, p.NewPrice)
+		}
+	}
+	items := []struct {
+		title string
+		list  []dawg.DiffItem
+	}{
+		{"Added products:", d.AddedProducts},
+		{"Removed products:", d.RemovedProducts},
+		{"Added variants:", d.AddedVariants},
+		{"Removed variants:", d.RemovedVariants},
+	}
+	for _, group := range items {
+		if len(group.list) == 0 {
+			continue
+		}
+		fmt.Fprintln(tw, group.title)
+		for _, item := range group.list {
+			fmt.Fprintf(tw, "  %s\t%s\n", item.Code, item.Name)
+		}
+	}
+	toppings := []struct {
+		title string
+		list  []dawg.DiffTopping
+	}{
+		{"Added toppings:", d.AddedToppings},
+		{"Removed toppings:", d.RemovedToppings},
+	}
+	for _, group := range toppings {
+		if len(group.list) == 0 {
+			continue
+		}
+		fmt.Fprintln(tw, group.title)
+		for _, t := range group.list {
+			fmt.Fprintf(tw, "  %s\t%s (%s)\n", t.Code, t.Name, t.ProductType)
+		}
+	}
+	return tw.Flush()
+}
+
+func (c *menuCmd) exportMenu() (err error) {
+	exporter, err := out.GetExporter(c.export)
+	if err != nil {
+		return err
+	}
+	if c.output == "" {
+		return exporter.Export(c.Output(), c.Menu())
+	}
+	f, err := os.Create(c.output)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Pair(err, f.Close()) }()
+	return exporter.Export(f, c.Menu())
+}
+
+func (c *menuCmd) search(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return errors.New("no search given")
+	}
+	if err := c.UpdateMenu(); err != nil {
+		return err
+	}
+	menu := c.Menu()
+	results := menu.Search(strings.Join(args, " "), dawg.SearchOptions{
+		Limit: c.searchLimit,
+		Exact: c.exact,
+	})
+	return printSearchResults(c.Output(), menu, results)
+}
+
+func printSearchResults(w io.Writer, m *dawg.Menu, results []dawg.SearchResult) error {
+	if len(results) == 0 {
+		_, err := fmt.Fprintln(w, "No menu items found.")
+		return err
+	}
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "CODE\tNAME\tPRICE\tCATEGORY")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Item.ItemCode(), r.Item.ItemName(),
+			eitherOr(itemPrice(m, r.Item), "-"), eitherOr(r.Category, "-"))
+	}
+	return tw.Flush()
+}
+
+// itemPrice finds the price of a menu item. Products only have a price if
+// they come in one size.
+func itemPrice(m *dawg.Menu, item dawg.Item) string {
+	code := item.ItemCode()
+	if p, ok := item.(*dawg.Product); ok && len(p.Variants) == 1 {
+		code = p.Variants[0]
+	}
+	if v, ok := m.Variants[code]; ok && !v.Price.IsZero() {
+		return v.Price.String()
+	}
+	return ""
+}
+
 func (c *menuCmd) printMenu(w io.Writer, name string) error {
 	out.SetOutput(w)
 	defer out.ResetOutput()
 	menu := c.Menu()
+	opts, err := c.menuOptions()
+	if err != nil {
+		return err
+	}
 	var allCategories = menu.Categorization.Food.Categories
 	if c.preconfigured {
 		allCategories = menu.Categorization.Preconfigured.Categories
@@ -148,12 +322,18 @@ func (c *menuCmd) printMenu(w io.Writer, name string) error {
 	}
 
 	if len(name) > 0 {
-		for _, cat := range allCategories {
-			if name == strings.ToLower(cat.Name) || name == strings.ToLower(cat.Code) {
-				return out.PrintMenu(cat, 0, menu)
+		// only look in the part of the menu that is being printed
+		cat, err := dawg.MenuCategory{Categories: allCategories}.Find(name)
+		if err != nil {
+			return err
+		}
+		if c.showCategories {
+			for _, sub := range cat.Categories {
+				fmt.Fprintln(w, strings.ToLower(path.Join(name, eitherOr(sub.Code, sub.Name))))
 			}
+			return nil
 		}
-		return fmt.Errorf("could not find %s", name)
+		return out.PrintMenuOpts(cat, 0, menu, opts)
 	} else if c.showCategories {
 		for _, cat := range allCategories {
 			if cat.Name != "" {
@@ -164,11 +344,30 @@ func (c *menuCmd) printMenu(w io.Writer, name string) error {
 	}
 
 	for _, cat := range allCategories {
-		out.PrintMenu(cat, 0, menu)
+		if err := out.PrintMenuOpts(cat, 0, menu, opts); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// menuOptions gets the options for printing the menu from the flags and
+// returns an error if they cannot be used.
+func (c *menuCmd) menuOptions() (out.MenuOptions, error) {
+	maxPrice, err := parseAmount("max-price", c.maxPrice, c.Store().Market().Currency)
+	if err != nil {
+		return out.MenuOptions{}, err
+	}
+	opts := out.MenuOptions{
+		SortBy:      strings.ToLower(c.sortBy),
+		MaxPrice:    maxPrice,
+		Vegetarian:  c.vegetarian,
+		GlutenFree:  c.glutenFree,
+		MaxCalories: c.maxCalories,
+	}
+	return opts, opts.Valid()
+}
+
 func (c *menuCmd) printToppings() {
 	var tops = c.Menu().Toppings
 
@@ -1,9 +1,14 @@
 package cmd
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
+	"github.com/harrybrwn/apizza/cmd/client"
 	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/dawg"
 	"github.com/harrybrwn/apizza/pkg/tests"
 )
 
@@ -28,7 +33,7 @@ func TestFindProduct(t *testing.T) {
 	defer r.CleanUp()
 	c := NewMenuCmd(r).(*menuCmd)
 
-	if err := r.DB().UpdateTS("menu", c); err != nil {
+	if err := c.UpdateMenu(); err != nil {
 		t.Error(err)
 	}
 	c.all = true
@@ -54,3 +59,171 @@ func TestStringStuff(t *testing.T) {
 		}
 	}
 }
+
+func TestPrintSearchResults(t *testing.T) {
+	tests.InitHelpers(t)
+	m := &dawg.Menu{
+		Products: map[string]*dawg.Product{
+			"S_PIZZA": {
+				ItemCommon: dawg.ItemCommon{Code: "S_PIZZA", Name: "Hand Tossed"},
+				Variants:   []string{"10SCREEN", "12SCREEN"},
+			},
+			"S_PEPPER": {
+				ItemCommon: dawg.ItemCommon{Code: "S_PEPPER", Name: "Pepperoni Feast"},
+				Variants:   []string{"14SCPEPPER"},
+			},
+		},
+		Variants: map[string]*dawg.Variant{
+			"10SCREEN":   {ItemCommon: dawg.ItemCommon{Code: "10SCREEN", Name: "Small Hand Tossed"}, Price: dawg.Cents(799), ProductCode: "S_PIZZA"},
+			"12SCREEN":   {ItemCommon: dawg.ItemCommon{Code: "12SCREEN", Name: "Medium Hand Tossed"}, Price: dawg.Cents(999), ProductCode: "S_PIZZA"},
+			"14SCPEPPER": {ItemCommon: dawg.ItemCommon{Code: "14SCPEPPER", Name: "Large Pepperoni Feast"}, Price: dawg.Cents(1599), ProductCode: "S_PEPPER"},
+		},
+	}
+	m.Categorization.Food.Categories = []dawg.MenuCategory{
+		{Name: "Pizza", Products: []string{"S_PIZZA"}},
+		{Name: "Specialty Pizza", Products: []string{"S_PEPPER"}},
+	}
+
+	buf := &bytes.Buffer{}
+	err := printSearchResults(buf, m, m.Search("medium hand tosed", dawg.SearchOptions{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := "CODE      NAME                PRICE  CATEGORY\n" +
+		"12SCREEN  Medium Hand Tossed  $9.99  Pizza\n"
+	if buf.String() != exp {
+		t.Errorf("wrong output:\n%q\nwant\n%q", buf.String(), exp)
+	}
+
+	buf.Reset()
+	tests.Check(printSearchResults(buf, m, m.Search("peperoni", dawg.SearchOptions{NoVariants: true})))
+	exp = "CODE      NAME             PRICE   CATEGORY\n" +
+		"S_PEPPER  Pepperoni Feast  $15.99  Specialty Pizza\n"
+	if buf.String() != exp {
+		t.Errorf("wrong output:\n%q\nwant\n%q", buf.String(), exp)
+	}
+
+	buf.Reset()
+	tests.Check(printSearchResults(buf, m, m.Search("hand tossed", dawg.SearchOptions{NoVariants: true})))
+	if !strings.Contains(buf.String(), "S_PIZZA  Hand Tossed  -      Pizza") {
+		t.Errorf("products with more than one size should not have a price:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	tests.Check(printSearchResults(buf, m, nil))
+	if buf.String() != "No menu items found.\n" {
+		t.Errorf("wrong output for no results: %q", buf.String())
+	}
+}
+
+func TestPrintMenuDiff(t *testing.T) {
+	tests.InitHelpers(t)
+	buf := &bytes.Buffer{}
+	tests.Check(printMenuDiff(buf, &dawg.MenuDiff{}))
+	tests.StrEq(buf.String(), "The menu has not changed.\n", "wrong output for an empty diff")
+
+	buf.Reset()
+	tests.Check(printMenuDiff(buf, &dawg.MenuDiff{
+		RemovedProducts: []dawg.DiffItem{{Code: "S_PEPPER", Name: "Pepperoni Feast"}},
+		AddedToppings:   []dawg.DiffTopping{{ProductType: "Wings", Code: "H", Name: "Hot Sauce"}},
+		PriceChanges: []dawg.PriceChange{
+			{Code: "12SCREEN", Name: "Medium Hand Tossed", OldPrice: dawg.Cents(999), NewPrice: dawg.Cents(1049)},
+			{Code: "10SCREEN", Name: "Small", OldPrice: dawg.Cents(799), NewPrice: dawg.Cents(749)},
+		},
+	}))
+	exp := `Price changes:
+  12SCREEN  Medium Hand Tossed  $9.99 -> $10.49
+  10SCREEN  Small               $7.99 -> $7.49
+Removed products:
+  S_PEPPER  Pepperoni Feast
+Added toppings:
+  H  Hot Sauce (Wings)
+`
+	tests.StrEq(buf.String(), exp, "wrong diff output:\n%s", buf.String())
+}
+
+type staticMenu struct {
+	data.MenuCacher
+	m *dawg.Menu
+}
+
+func (s *staticMenu) Menu() *dawg.Menu { return s.m }
+
+type staticStore struct {
+	client.StoreFinder
+	s *dawg.Store
+}
+
+func (s *staticStore) Store() *dawg.Store { return s.s }
+
+func TestPrintMenuPath(t *testing.T) {
+	tests.InitHelpers(t)
+	m := &dawg.Menu{
+		Products: map[string]*dawg.Product{
+			"S_PIZZA":  {ItemCommon: dawg.ItemCommon{Code: "S_PIZZA", Name: "Hand Tossed"}, Variants: []string{"12SCREEN"}},
+			"S_PEPPER": {ItemCommon: dawg.ItemCommon{Code: "S_PEPPER", Name: "Pepperoni Feast"}, Variants: []string{"14SCPEPPER"}},
+		},
+		Variants: map[string]*dawg.Variant{
+			"12SCREEN":   {ItemCommon: dawg.ItemCommon{Code: "12SCREEN", Name: "Medium Hand Tossed"}, Price: dawg.Cents(999), ProductCode: "S_PIZZA"},
+			"14SCPEPPER": {ItemCommon: dawg.ItemCommon{Code: "14SCPEPPER", Name: "Large Pepperoni Feast"}, Price: dawg.Cents(1599), ProductCode: "S_PEPPER"},
+		},
+	}
+	m.Categorization.Food.Categories = []dawg.MenuCategory{
+		{Name: "Pizza", Code: "Pizza", Categories: []dawg.MenuCategory{
+			{Name: "Build Your Own", Code: "BuildYourOwn", Products: []string{"S_PIZZA"}},
+			{Name: "Specialty Pizzas", Code: "Specialty", Products: []string{"S_PEPPER"}},
+		}},
+	}
+	c := &menuCmd{MenuCacher: &staticMenu{m: m}, StoreFinder: &staticStore{s: &dawg.Store{ID: "4336"}}}
+	buf := &bytes.Buffer{}
+
+	tests.Check(c.printMenu(buf, "pizza/specialty"))
+	if !strings.Contains(buf.String(), "14SCPEPPER") || strings.Contains(buf.String(), "12SCREEN") {
+		t.Errorf("should only print the specialty pizzas:\n%s", buf.String())
+	}
+	buf.Reset()
+	tests.Check(c.printMenu(buf, "build your own"))
+	if !strings.Contains(buf.String(), "12SCREEN") {
+		t.Errorf("should find nested categories by name:\n%s", buf.String())
+	}
+	buf.Reset()
+	c.showCategories = true
+	tests.Check(c.printMenu(buf, "pizza"))
+	tests.StrEq(buf.String(), "pizza/buildyourown\npizza/specialty\n", "wrong sub-categories")
+	tests.Exp(c.printMenu(buf, "pizza/wings"))
+
+	c.showCategories = false
+	c.maxPrice = "$10"
+	buf.Reset()
+	tests.Check(c.printMenu(buf, "pizza"))
+	if !strings.Contains(buf.String(), "12SCREEN") || strings.Contains(buf.String(), "14SCPEPPER") {
+		t.Errorf("should only print the items under the max price:\n%s", buf.String())
+	}
+	opts, err := c.menuOptions()
+	tests.Check(err)
+	tests.StrEq(opts.MaxPrice.Currency, "USD", "the max price should be in the market's currency")
+	c.maxPrice = "ten"
+	tests.Exp(c.printMenu(buf, "pizza"), "should not take a bad max price")
+	c.maxPrice = "-1"
+	tests.Exp(c.printMenu(buf, "pizza"), "should not take a negative max price")
+	c.maxPrice = ""
+
+	m.Preconfigured = map[string]*dawg.PreConfiguredProduct{
+		"14SCEXTRAV": {ItemCommon: dawg.ItemCommon{Code: "14SCEXTRAV", Name: "Large ExtravaganZZa"}},
+	}
+	m.Categorization.Preconfigured.Categories = []dawg.MenuCategory{
+		{Name: "Popular Items", Code: "PopularItems", Products: []string{"14SCEXTRAV"}},
+	}
+	c.showCategories = false
+	buf.Reset()
+	tests.Exp(c.printMenu(buf, "popularitems"), "pre-configured categories need -p or -a")
+	c.preconfigured = true
+	tests.Check(c.printMenu(buf, "popularitems"))
+	if !strings.Contains(buf.String(), "14SCEXTRAV") {
+		t.Errorf("should print the pre-configured category:\n%s", buf.String())
+	}
+	tests.Exp(c.printMenu(buf, "pizza"), "food categories are not pre-configured")
+	c.preconfigured, c.all = false, true
+	tests.Check(c.printMenu(buf, "popularitems"))
+	tests.Check(c.printMenu(buf, "pizza"))
+}
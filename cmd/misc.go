@@ -8,7 +8,9 @@ import (
 	"strings"
 
 	"github.com/harrybrwn/apizza/cmd/cli"
+	"github.com/harrybrwn/apizza/cmd/client"
 	"github.com/harrybrwn/apizza/cmd/internal/obj"
+	"github.com/harrybrwn/apizza/dawg"
 	"github.com/harrybrwn/apizza/pkg/cache"
 	"github.com/spf13/cobra"
 )
@@ -16,25 +18,33 @@ import (
 // NewAddAddressCmd creates the 'add-address' command.
 func NewAddAddressCmd(b cli.Builder, in io.Reader) cli.CliCommand {
 	c := &addAddressCmd{
-		db:  b.DB(),
-		in:  in,
-		new: false,
+		UserFinder: client.NewUserGetter(b, in),
+		db:         b.DB(),
+		market:     func() *dawg.Market { return b.Config().GetMarket() },
+		in:         in,
+		new:        false,
 	}
 	c.CliCommand = b.Build("address", "Add a new named address to the internal storage.", c)
 	cmd := c.Cmd()
 	cmd.Aliases = []string{"addr"}
 	cmd.Flags().BoolVarP(&c.new, "new", "n", c.new, "add a new address")
 	cmd.Flags().StringVarP(&c.delete, "delete", "d", "", "delete an address")
+	cmd.Flags().BoolVar(&c.push, "push", false, "save the named addresses to your dominos account")
+	cmd.Flags().BoolVar(&c.pull, "pull", false, "save your dominos account's addresses as named addresses")
 	return c
 }
 
 type addAddressCmd struct {
 	cli.CliCommand
+	client.UserFinder
 
 	db     *cache.DataBase
+	market func() *dawg.Market
 	in     io.Reader
 	new    bool
 	delete string
+	push   bool
+	pull   bool
 }
 
 func (a *addAddressCmd) Run(cmd *cobra.Command, args []string) error {
@@ -45,6 +55,25 @@ func (a *addAddressCmd) Run(cmd *cobra.Command, args []string) error {
 		db := a.db.WithBucket("addresses")
 		return db.Delete(a.delete)
 	}
+	if a.push || a.pull {
+		user, err := a.User()
+		if err != nil {
+			return err
+		}
+		if a.pull {
+			if err = pullAddresses(a.db, user); err != nil {
+				return err
+			}
+		}
+		if a.push {
+			if err = pushAddresses(a.db, user); err != nil {
+				return err
+			}
+			if err = user.Update(); err != nil {
+				return err
+			}
+		}
+	}
 
 	m, err := a.db.WithBucket("addresses").Map()
 	if err != nil {
@@ -59,6 +88,65 @@ func (a *addAddressCmd) Run(cmd *cobra.Command, args []string) error {
 		}
 
 		a.Printf("%s:\n  %s\n", key, obj.AddressFmtIndent(addr, 2))
+		if addr.DeliveryInstructions != "" {
+			a.Printf("  %s\n", addr.DeliveryInstructions)
+		}
+	}
+	return nil
+}
+
+// pushAddresses adds the named addresses in the database to the user's
+// profile. Account addresses with the same name are updated in place so that
+// dominos-only fields like delivery instructions are kept.
+func pushAddresses(db *cache.DataBase, user *dawg.UserProfile) error {
+	m, err := db.WithBucket("addresses").Map()
+	if err != nil {
+		return err
+	}
+	names := make(map[string]*dawg.UserAddress, len(user.Addresses))
+	for _, ua := range user.Addresses {
+		names[ua.Name] = ua
+	}
+	for name, raw := range m {
+		addr, err := obj.FromGob(raw)
+		if err != nil {
+			return err
+		}
+		ua, ok := names[name]
+		if !ok {
+			ua = user.AddAddress(addr)
+			ua.Name = name
+			continue
+		}
+		ua.SetStreet(addr.LineOne())
+		ua.CityName = addr.City()
+		ua.Region = addr.StateCode()
+		ua.PostalCode = addr.Zip()
+		if addr.UnitNumber != "" {
+			ua.UnitType, ua.UnitNumber = addr.Unit()
+		}
+		if addr.DeliveryInstructions != "" {
+			ua.DeliveryInstructions = addr.DeliveryInstructions
+		}
+	}
+	return nil
+}
+
+// pullAddresses saves the user's account addresses as named addresses.
+// Addresses without a name on the account are named after their street.
+func pullAddresses(db *cache.DataBase, user *dawg.UserProfile) error {
+	for _, ua := range user.Addresses {
+		name := ua.Name
+		if name == "" {
+			name = ua.LineOne()
+		}
+		raw, err := obj.AsGob(obj.FromAddress(ua))
+		if err != nil {
+			return err
+		}
+		if err = db.WithBucket("addresses").Put(name, raw); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -81,21 +169,40 @@ func (a *addAddressCmd) newAddress() error {
 	if err != nil {
 		return err
 	}
+	a.Printf("Unit (like 'Apt 4B', optional): ")
+	unit, err := r.readline()
+	if err != nil {
+		return err
+	}
+	addr.UnitType, addr.UnitNumber = splitUnit(unit)
 	a.Printf("City: ")
 	addr.CityName, err = r.readline()
 	if err != nil {
 		return err
 	}
-	a.Printf("State Code: ")
+	market := a.market()
+	a.Printf("%s Code: ", strings.Title(market.RegionName))
 	addr.State, err = r.readline()
 	if err != nil {
 		return err
 	}
-	a.Printf("Zipcode: ")
+	a.Printf("%s: ", strings.Title(market.PostalName))
 	addr.Zipcode, err = r.readline()
 	if err != nil {
 		return err
 	}
+	if !market.ValidRegion(addr.State) {
+		return fmt.Errorf("%q is not a %s in %s", addr.State, market.RegionName, market.Name)
+	}
+	if addr.Zipcode, err = market.PostalCode(addr.Zipcode); err != nil {
+		return err
+	}
+
+	a.Printf("Delivery Instructions (optional): ")
+	addr.DeliveryInstructions, err = r.readline()
+	if err != nil {
+		return err
+	}
 
 	fmt.Print(name, ":\n", addr, "\n")
 	raw, err := obj.AsGob(&addr)
@@ -105,6 +212,19 @@ func (a *addAddressCmd) newAddress() error {
 	return a.db.WithBucket("addresses").Put(name, raw)
 }
 
+// splitUnit splits a unit like "Apt 4B" into its type and number. A unit
+// with only one word is taken to be the number.
+func splitUnit(unit string) (unitType, number string) {
+	fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(unit), "#"))
+	switch len(fields) {
+	case 0:
+		return "", ""
+	case 1:
+		return "", fields[0]
+	}
+	return strings.Join(fields[:len(fields)-1], " "), fields[len(fields)-1]
+}
+
 func (r *reader) readline() (string, error) {
 	lineone, err := r.scanner.ReadString('\n')
 	if err != nil {
@@ -9,6 +9,7 @@ type CliFlags struct {
 
 	ClearCache bool
 	ResetMenu  bool
+	Offline    bool
 	LogFile    string
 }
 
@@ -16,11 +17,12 @@ type CliFlags struct {
 func (rf *CliFlags) Install(persistflags *pflag.FlagSet) {
 	rf.ClearCache = false
 	// persistflags.BoolVar(&rf.ClearCache, "clear-cache", false, "delete the database")
-	persistflags.BoolVar(&rf.ResetMenu, "delete-menu", false, "delete the menu stored in cache")
+	persistflags.BoolVar(&rf.ResetMenu, "delete-menu", false, "delete the menus stored in cache")
+	persistflags.BoolVar(&rf.Offline, "offline", false, "only use saved data and never connect to dominos")
 	persistflags.StringVar(&rf.LogFile, "log", "", "set a log file (found in ~/.config/apizza/logs)")
 
 	persistflags.StringVarP(&rf.Address, "address", "A", rf.Address, "an address name stored with 'apizza address --new' or a parsable address")
-	persistflags.StringVar(&rf.Service, "service", rf.Service, "select a Dominos service, either 'Delivery' or 'Carryout'")
+	persistflags.StringVar(&rf.Service, "service", rf.Service, "select a Dominos service: 'Delivery', 'Carryout', 'DriveUpCarryout' (curbside), or 'DineIn'")
 }
 
 // ApizzaFlags that are not persistant.
@@ -0,0 +1,333 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/harrybrwn/apizza/cmd/cli"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
+)
+
+// scheduled runs that are found more than this long after they were due are
+// reported as missed instead of being sent late.
+const missedAfter = 5 * time.Minute
+
+// `apizza schedule`
+type scheduleCmd struct {
+	cli.CliCommand
+	db    *cache.DataBase
+	order *orderCmd
+
+	cron   string
+	dryRun bool
+
+	now      func() time.Time
+	validate func(o *dawg.Order, now time.Time) error
+}
+
+// NewScheduleCmd creates the 'schedule' command.
+func NewScheduleCmd(b cli.Builder) cli.CliCommand {
+	c := &scheduleCmd{
+		db:       b.DB(),
+		order:    newOrderSender(b),
+		now:      time.Now,
+		validate: checkCart,
+	}
+	c.CliCommand = b.Build("schedule", "Send orders from the cart on a schedule.", c)
+	c.order.CliCommand = c.CliCommand
+	c.Cmd().Long = `The schedule command sends saved orders to dominos on a cron schedule.
+
+Schedules use the usual five cron fields: minute, hour, day of month, month,
+and day of week. For example, '0 12 * * FRI' is every friday at noon.
+
+Scheduled orders are only sent while 'apizza schedule run' is running. Runs that
+come due while it is not running are reported and never sent late.`
+
+	add := b.Build("add <cart>", "Schedule a saved order", cli.RunFunction(c.add))
+	add.Flags().StringVar(&c.cron, "cron", "", "the cron expression for when to send the order")
+
+	run := b.Build("run", "Run the scheduler in the foreground", cli.RunFunction(c.run))
+	run.Cmd().Long = `Run the scheduler in the foreground until it is stopped with ctrl-c.
+
+Before a scheduled order is sent, the store must be open for the order's service
+method and every product must still be on the store's menu. The duplicate order
+guard and the budgets in the config are checked as well. Orders that are sent
+are added to 'apizza history', and every run, including dry runs, failures, and
+missed runs, is written to the log and to 'apizza schedule history'.
+
+The card vault passphrase is read from $APIZZA_CARD_PASSPHRASE or prompted for
+once when the scheduler starts. Other apizza commands will wait for the
+scheduler to stop because it keeps the database open.`
+	run.Flags().BoolVar(&c.dryRun, "dry-run", false, "only price the orders instead of sending them")
+	run.Flags().IntVar(&c.order.cvv, "cvv", 0, "the card's cvv number used for every scheduled order")
+
+	c.Addcmd(
+		add,
+		b.Build("list", "List the schedules", cli.RunFunction(c.list)),
+		b.Build("remove <cart>", "Remove the schedule for a saved order", cli.RunFunction(c.remove)),
+		b.Build("history [cart]", "Show the results of the scheduled runs", cli.RunFunction(c.history)),
+		run,
+	)
+	return c
+}
+
+// Run will list the schedules.
+func (c *scheduleCmd) Run(cmd *cobra.Command, args []string) error {
+	return c.list(cmd, args)
+}
+
+func (c *scheduleCmd) add(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("give one saved order to schedule")
+	}
+	if c.cron == "" {
+		return errors.New("no schedule given (see --cron)")
+	}
+	if _, err := data.GetOrder(args[0], c.db); err != nil {
+		return err
+	}
+	s, err := data.NewSchedule(args[0], c.cron)
+	if err != nil {
+		return err
+	}
+	s.Created, s.LastRun = c.now(), c.now()
+	if err = data.SaveSchedule(c.db, s); err != nil {
+		return err
+	}
+	c.Printf("scheduled '%s', next run at %s\n", s.Cart, s.Next().Format(scheduleTimeFmt))
+	return nil
+}
+
+func (c *scheduleCmd) remove(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("give one saved order")
+	}
+	return data.DeleteSchedule(c.db, args[0])
+}
+
+const scheduleTimeFmt = "Mon 2006-01-02 15:04"
+
+func (c *scheduleCmd) list(cmd *cobra.Command, args []string) error {
+	schedules, err := data.Schedules(c.db)
+	if err != nil {
+		return err
+	}
+	if len(schedules) == 0 {
+		c.Println("No orders are scheduled.")
+		return nil
+	}
+	tw := newTabWriter(c.Output())
+	fmt.Fprintln(tw, "CART\tCRON\tNEXT RUN\tLAST STATUS")
+	for _, s := range schedules {
+		var next string
+		if t := s.Next(); !t.IsZero() {
+			next = t.Format(scheduleTimeFmt)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", s.Cart, s.Cron, next, s.LastStatus)
+	}
+	return tw.Flush()
+}
+
+func (c *scheduleCmd) history(cmd *cobra.Command, args []string) error {
+	var cart string
+	if len(args) > 0 {
+		cart = args[0]
+	}
+	runs, err := data.ScheduleRuns(c.db, cart)
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		c.Println("No scheduled orders have run.")
+		return nil
+	}
+	tw := newTabWriter(c.Output())
+	fmt.Fprintln(tw, "DUE\tCART\tSTATUS\tRESULT")
+	for _, r := range runs {
+		var result string
+		switch {
+		case r.Error != "":
+			result = r.Error
+		case r.OrderID != "":
+			result = "order id: " + r.OrderID
+		case r.Missed > 0:
+			result = fmt.Sprintf("%d run(s) missed", r.Missed)
+		case !r.Price.IsZero():
+			result = r.Price.Decimal()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Due.Format(scheduleTimeFmt), r.Cart, r.Status, result)
+	}
+	return tw.Flush()
+}
+
+func (c *scheduleCmd) run(cmd *cobra.Command, args []string) error {
+	if !c.dryRun {
+		if c.order.cvv == 0 {
+			return errors.New("must have cvv number to send orders. (see --cvv)")
+		}
+		// unlock the card vault now so there is no prompt later on
+		if _, err := c.order.payment(); err != nil {
+			return err
+		}
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	c.Println("running the scheduler, press ctrl-c to stop")
+	for {
+		if err := c.tick(c.now()); err != nil {
+			return err
+		}
+		now := c.now()
+		select {
+		case <-stop:
+			c.Println("stopping the scheduler")
+			return nil
+		case <-time.After(now.Truncate(time.Minute).Add(time.Minute).Sub(now)):
+		}
+	}
+}
+
+// tick sends the scheduled orders that are due at the time now and reports
+// the runs that were missed.
+func (c *scheduleCmd) tick(now time.Time) error {
+	schedules, err := data.Schedules(c.db)
+	if err != nil {
+		return err
+	}
+	for _, s := range schedules {
+		spec, err := s.Parse()
+		if err != nil {
+			logger.Error("bad schedule", "cart", s.Cart, "cron", s.Cron, "error", err)
+			continue
+		}
+		runs := spec.Between(s.LastRun, now)
+		s.LastRun = now
+		if len(runs) == 0 {
+			continue
+		}
+
+		due := runs[len(runs)-1]
+		if now.Sub(due) > missedAfter {
+			c.missed(s, runs)
+		} else {
+			if len(runs) > 1 {
+				c.missed(s, runs[:len(runs)-1])
+			}
+			c.fire(s, due)
+		}
+		if err = data.SaveSchedule(c.db, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *scheduleCmd) missed(s *data.Schedule, runs []time.Time) {
+	last := runs[len(runs)-1]
+	c.Printf("%s: missed %d scheduled run(s) of '%s', the last was due %s; the order was not sent\n",
+		c.now().Format(scheduleTimeFmt), len(runs), s.Cart, last.Format(scheduleTimeFmt))
+	logger.Warn("missed scheduled order", "cart", s.Cart, "runs", len(runs), "last", last.Format(time.RFC3339))
+	s.LastStatus, s.LastError = data.ScheduleMissed, ""
+	c.record(&data.ScheduleRun{
+		Cart: s.Cart, Due: last, Time: c.now(), Status: data.ScheduleMissed, Missed: len(runs),
+	})
+}
+
+func (c *scheduleCmd) fire(s *data.Schedule, due time.Time) {
+	run := &data.ScheduleRun{Cart: s.Cart, Due: due, Time: c.now()}
+	if err := c.send(run); err != nil {
+		run.Status, run.Error = data.ScheduleFailed, err.Error()
+		c.Printf("%s: could not send '%s': %v\n", due.Format(scheduleTimeFmt), s.Cart, err)
+		logger.Error("scheduled order failed", "cart", s.Cart, "error", err)
+	} else {
+		logger.Info("scheduled order "+run.Status, "cart", s.Cart, "dry-run", c.dryRun)
+	}
+	s.LastStatus, s.LastError = run.Status, run.Error
+	c.record(run)
+}
+
+// record adds a run to the schedule history. The scheduler keeps going if it
+// cannot be saved.
+func (c *scheduleCmd) record(run *data.ScheduleRun) {
+	if err := data.SaveScheduleRun(c.db, run); err != nil {
+		logger.Error("could not save scheduled run", "cart", run.Cart, "error", err)
+	}
+}
+
+// send validates and sends one scheduled order, or only prices it for a dry
+// run, and fills in the run's results.
+func (c *scheduleCmd) send(run *data.ScheduleRun) error {
+	order, err := data.GetOrder(run.Cart, c.db)
+	if err != nil {
+		return err
+	}
+	if c.dryRun {
+		// payment is not needed for pricing
+		order.SetMarket(c.order.conf.GetMarket())
+		order.Address = dawg.StreetAddrFromAddress(c.order.getaddress())
+	} else if err = c.order.fill(order); err != nil {
+		return err
+	}
+	if err = c.validate(order, run.Due); err != nil {
+		return err
+	}
+
+	if c.dryRun {
+		price, err := c.order.price(order)
+		if err != nil {
+			return err
+		}
+		c.Printf("%s: would send '%s' for %s\n", run.Due.Format(scheduleTimeFmt), run.Cart, price.Decimal())
+		run.Status, run.Price = data.SchedulePriced, price
+		return nil
+	}
+	if err = c.order.checkDuplicate(order); err != nil {
+		return err
+	}
+	if err = c.order.checkBudget(order); err != nil {
+		return err
+	}
+	if err = c.order.send(order); err != nil {
+		return err
+	}
+	c.Printf("%s: sent '%s', order id: %s\n", run.Due.Format(scheduleTimeFmt), run.Cart, order.OrderID)
+	run.Status, run.OrderID = data.SchedulePlaced, order.OrderID
+	return nil
+}
+
+// checkCart makes sure that an order can still be sent to its store: the
+// store has to be open for the order's service method and every product has
+// to still be on the menu.
+func checkCart(o *dawg.Order, now time.Time) error {
+	store, err := o.Market().NewStore(o.StoreID, o.ServiceMethod, o.Address)
+	if err != nil {
+		return err
+	}
+	if err = store.CheckHours(o.ServiceMethod, now); err != nil {
+		return err
+	}
+	menu, err := store.Menu()
+	if err != nil {
+		return err
+	}
+	for _, p := range o.Products {
+		if menu.FindItem(p.Code) == nil {
+			return fmt.Errorf("%s is no longer on the menu at store %s", p.Code, o.StoreID)
+		}
+	}
+	if err = o.Validate(); err != nil && !dawg.IsWarning(err) {
+		return err
+	}
+	return nil
+}
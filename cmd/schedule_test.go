@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestScheduleCmd(t *testing.T) {
+	tests.InitHelpers(t)
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	c := NewScheduleCmd(r).(*scheduleCmd)
+	start := time.Date(2026, time.October, 18, 9, 0, 0, 0, time.Local) // a sunday
+	now := start
+	c.now = func() time.Time { return now }
+	c.validate = func(*dawg.Order, time.Time) error { return nil }
+	c.order.price = func(*dawg.Order) (dawg.Money, error) { return dawg.Cents(2150), nil }
+	c.dryRun = true
+
+	o := &dawg.Order{StoreID: "4336", ServiceMethod: dawg.Carryout,
+		Products: []*dawg.OrderProduct{{ItemCommon: dawg.ItemCommon{Code: "14SCREEN"}, Qty: 1}}}
+	raw, err := json.Marshal(o)
+	tests.Check(err)
+	tests.Check(r.DB().Put(data.OrderPrefix+"lunch", raw))
+
+	tests.Check(c.list(c.Cmd(), nil))
+	r.Compare(t, "No orders are scheduled.\n")
+	r.ClearBuf()
+
+	tests.Exp(c.add(c.Cmd(), []string{"lunch"}), "should need --cron")
+	c.cron = "0 12 * * FRI"
+	tests.Exp(c.add(c.Cmd(), []string{"nothing"}), "should need a saved order")
+	tests.Check(c.add(c.Cmd(), []string{"lunch"}))
+	r.Compare(t, "scheduled 'lunch', next run at Fri 2026-10-23 12:00\n")
+	r.ClearBuf()
+
+	// nothing is due yet
+	now = start.Add(time.Hour)
+	tests.Check(c.tick(now))
+	tests.StrEq(r.Out.String(), "", "nothing should run before friday")
+
+	now = time.Date(2026, time.October, 23, 12, 0, 30, 0, time.Local)
+	tests.Check(c.tick(now))
+	r.Compare(t, "Fri 2026-10-23 12:00: would send 'lunch' for 21.50\n")
+	r.ClearBuf()
+	s, err := data.GetSchedule(r.DB(), "lunch")
+	tests.Check(err)
+	tests.StrEq(s.LastStatus, data.SchedulePriced, "wrong status")
+	tests.Check(c.tick(now.Add(time.Minute)))
+	tests.StrEq(r.Out.String(), "", "should not run twice")
+
+	// the scheduler was down for two fridays
+	now = time.Date(2026, time.November, 6, 15, 0, 0, 0, time.Local)
+	tests.Check(c.tick(now))
+	if !r.Contains("missed 2 scheduled run(s) of 'lunch'") || r.Contains("would send") {
+		t.Errorf("missed runs should be reported and not sent:\n%s", r.Out.String())
+	}
+	r.ClearBuf()
+	s, err = data.GetSchedule(r.DB(), "lunch")
+	tests.Check(err)
+	tests.StrEq(s.LastStatus, data.ScheduleMissed, "wrong status")
+
+	c.validate = func(*dawg.Order, time.Time) error { return errors.New("store is closed") }
+	now = time.Date(2026, time.November, 13, 12, 1, 0, 0, time.Local)
+	tests.Check(c.tick(now))
+	if !r.Contains("could not send 'lunch': store is closed") {
+		t.Errorf("failures should be reported:\n%s", r.Out.String())
+	}
+	r.ClearBuf()
+	s, err = data.GetSchedule(r.DB(), "lunch")
+	tests.Check(err)
+	tests.StrEq(s.LastStatus, data.ScheduleFailed, "wrong status")
+	tests.StrEq(s.LastError, "store is closed", "wrong error")
+
+	tests.Check(c.list(c.Cmd(), nil))
+	r.Compare(t, `CART   CRON          NEXT RUN              LAST STATUS
+lunch  0 12 * * FRI  Fri 2026-11-20 12:00  failed
+`)
+	r.ClearBuf()
+
+	// every run is kept in the schedule history
+	tests.Check(c.history(c.Cmd(), []string{"lunch"}))
+	r.Compare(t, `DUE                   CART   STATUS  RESULT
+Fri 2026-11-13 12:00  lunch  failed  store is closed
+Fri 2026-11-06 12:00  lunch  missed  2 run(s) missed
+Fri 2026-10-23 12:00  lunch  priced  21.50
+`)
+	r.ClearBuf()
+	tests.Check(c.history(c.Cmd(), []string{"dinner"}))
+	r.Compare(t, "No scheduled orders have run.\n")
+	r.ClearBuf()
+
+	c.dryRun = false
+	tests.Exp(c.run(c.Cmd(), nil), "should need a cvv to send orders")
+	tests.Check(c.remove(c.Cmd(), []string{"lunch"}))
+	tests.Exp(c.remove(c.Cmd(), []string{"lunch"}))
+}
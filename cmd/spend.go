@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/harrybrwn/apizza/cmd/cli"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
+)
+
+// `apizza spend`
+type spendCmd struct {
+	cli.CliCommand
+	db   *cache.DataBase
+	conf *cli.Config
+
+	by    string
+	since string
+	until string
+	store string
+}
+
+// NewSpendCmd creates the 'spend' command.
+func NewSpendCmd(b cli.Builder) cli.CliCommand {
+	c := &spendCmd{db: b.DB(), conf: b.Config(), by: "month"}
+	c.CliCommand = b.Build("spend", "Show how much has been spent on orders.", c)
+	c.Cmd().Long = `The spend command adds up the orders that have been sent (see 'apizza history').
+
+Totals can be grouped by week, month, store, or product. Dominos only prices
+whole orders, so the product totals are estimates that split each order's total
+between its products by quantity.
+
+If a budget is set in the config with 'budget.order' or 'budget.monthly',
+the amount left in this month's budget is shown as well.`
+
+	flags := c.Cmd().Flags()
+	flags.StringVar(&c.by, "by", c.by, "group the totals by week, month, store, or product")
+	flags.StringVar(&c.since, "since", "", "only count orders sent on or after this date (yyyy-mm-dd)")
+	flags.StringVar(&c.until, "until", "", "only count orders sent on or before this date (yyyy-mm-dd)")
+	flags.StringVar(&c.store, "store", "", "only count orders sent to this store id")
+	return c
+}
+
+// Run will print the spending totals.
+func (c *spendCmd) Run(cmd *cobra.Command, args []string) error {
+	group, ok := spendGroups[c.by]
+	if !ok {
+		return fmt.Errorf("cannot group totals by '%s'", c.by)
+	}
+	f, err := historyFilter(c.since, c.until, c.store)
+	if err != nil {
+		return err
+	}
+	// only orders in one currency can be added up
+	currency := c.conf.GetMarket().Currency
+	f.Currency = currency
+	orders, err := data.PlacedOrders(c.db, f)
+	if err != nil {
+		return err
+	}
+	if len(orders) == 0 {
+		c.Println("No orders have been sent.")
+		return nil
+	}
+	if err = printSpending(c.Output(), c.by, group(orders)); err != nil {
+		return err
+	}
+
+	monthly, err := parseBudget(c.conf.Budget.Monthly, currency)
+	if err != nil || monthly.IsZero() {
+		return err
+	}
+	spent, err := monthlySpending(c.db, time.Now(), currency)
+	if err != nil {
+		return err
+	}
+	c.Printf("\n%s of the %s monthly budget is left\n", monthly.Sub(spent).Decimal(), monthly.Decimal())
+	return nil
+}
+
+// spendRow is one line of a spending report.
+type spendRow struct {
+	key    string
+	orders int
+	qty    int
+	total  dawg.Money
+}
+
+var spendGroups = map[string]func([]*data.PlacedOrder) []*spendRow{
+	"week": func(orders []*data.PlacedOrder) []*spendRow {
+		return spendByPeriod(orders, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		})
+	},
+	"month": func(orders []*data.PlacedOrder) []*spendRow {
+		return spendByPeriod(orders, func(t time.Time) string {
+			return t.Format("2006-01")
+		})
+	},
+	"store":   spendByStore,
+	"product": spendByProduct,
+}
+
+// spendByPeriod groups orders by the period that they were sent in. The most
+// recent period is first.
+func spendByPeriod(orders []*data.PlacedOrder, period func(time.Time) string) []*spendRow {
+	rows := groupSpending(orders, func(po *data.PlacedOrder, add func(string, int, dawg.Money)) {
+		add(period(po.Time.Local()), 1, po.Total())
+	})
+	sort.Slice(rows, func(i, j int) bool { return rows[i].key > rows[j].key })
+	return rows
+}
+
+func spendByStore(orders []*data.PlacedOrder) []*spendRow {
+	rows := groupSpending(orders, func(po *data.PlacedOrder, add func(string, int, dawg.Money)) {
+		add(po.StoreID, 1, po.Total())
+	})
+	sortByTotal(rows)
+	return rows
+}
+
+func spendByProduct(orders []*data.PlacedOrder) []*spendRow {
+	rows := groupSpending(orders, func(po *data.PlacedOrder, add func(string, int, dawg.Money)) {
+		var units int
+		for _, p := range po.Products {
+			units += p.Qty
+		}
+		if units == 0 {
+			return
+		}
+		qtys := make([]int, len(po.Products))
+		for i, p := range po.Products {
+			qtys[i] = p.Qty
+		}
+		for i, share := range po.Total().Allocate(qtys) {
+			add(po.Products[i].Code, po.Products[i].Qty, share)
+		}
+	})
+	sortByTotal(rows)
+	return rows
+}
+
+// groupSpending builds spending rows with a function that adds a placed
+// order's spending to one or more groups.
+func groupSpending(
+	orders []*data.PlacedOrder,
+	each func(po *data.PlacedOrder, add func(key string, qty int, total dawg.Money)),
+) []*spendRow {
+	groups := map[string]*spendRow{}
+	rows := make([]*spendRow, 0)
+	for _, po := range orders {
+		seen := map[string]bool{}
+		each(po, func(key string, qty int, total dawg.Money) {
+			row, ok := groups[key]
+			if !ok {
+				row = &spendRow{key: key}
+				groups[key] = row
+				rows = append(rows, row)
+			}
+			if !seen[key] {
+				row.orders++
+				seen[key] = true
+			}
+			row.qty += qty
+			row.total = row.total.Add(total)
+		})
+	}
+	return rows
+}
+
+func sortByTotal(rows []*spendRow) {
+	sort.Slice(rows, func(i, j int) bool {
+		if c := rows[i].total.Cmp(rows[j].total); c != 0 {
+			return c > 0
+		}
+		return rows[i].key < rows[j].key
+	})
+}
+
+func printSpending(w io.Writer, by string, rows []*spendRow) error {
+	var total dawg.Money
+	tw := newTabWriter(w)
+	if by == "product" {
+		fmt.Fprintln(tw, "PRODUCT\tQTY\tORDERS\tTOTAL")
+	} else {
+		fmt.Fprintf(tw, "%s\tORDERS\tTOTAL\n", strings.ToUpper(by))
+	}
+	for _, row := range rows {
+		total = total.Add(row.total)
+		if by == "product" {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%s\n", row.key, row.qty, row.orders, row.total.Decimal())
+		} else {
+			fmt.Fprintf(tw, "%s\t%d\t%s\n", row.key, row.orders, row.total.Decimal())
+		}
+	}
+	if by == "product" {
+		fmt.Fprintf(tw, "total\t\t\t%s\n", total.Decimal())
+	} else {
+		fmt.Fprintf(tw, "total\t\t%s\n", total.Decimal())
+	}
+	return tw.Flush()
+}
+
+// monthlySpending is the total of the orders paid in a currency that were sent
+// in the same month as now.
+func monthlySpending(db *cache.DataBase, now time.Time, currency string) (dawg.Money, error) {
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	orders, err := data.PlacedOrders(db, &data.HistoryFilter{Since: start, Currency: currency})
+	if err != nil {
+		return dawg.Money{}, err
+	}
+	spent := dawg.Money{Currency: currency}
+	for _, po := range orders {
+		spent = spent.Add(po.Total())
+	}
+	return spent, nil
+}
+
+// parseBudget parses a budget from the config in the given currency. An empty
+// budget is zero, which means there is no limit.
+func parseBudget(s, currency string) (dawg.Money, error) {
+	if strings.TrimSpace(s) == "" {
+		return dawg.Money{}, nil
+	}
+	budget, err := dawg.ParseMoney(s)
+	if err != nil || budget.Units < 0 {
+		return dawg.Money{}, fmt.Errorf("bad budget '%s' in config: must be a positive amount", strings.TrimSpace(s))
+	}
+	return budget.In(currency), nil
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestSpendCmd(t *testing.T) {
+	tests.InitHelpers(t)
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	c := NewSpendCmd(r).(*spendCmd)
+
+	tests.Check(c.Run(c.Cmd(), nil))
+	r.Compare(t, "No orders have been sent.\n")
+	r.ClearBuf()
+	testHistory(t, r)
+
+	tests.Check(c.Run(c.Cmd(), nil))
+	r.Compare(t, `MONTH    ORDERS  TOTAL
+2026-01  2       30.00
+total            30.00
+`)
+	r.ClearBuf()
+
+	c.by = "week"
+	tests.Check(c.Run(c.Cmd(), nil))
+	r.Compare(t, `WEEK      ORDERS  TOTAL
+2026-W03  1       4.50
+2026-W02  1       25.50
+total             30.00
+`)
+	r.ClearBuf()
+
+	c.by = "product"
+	c.store = "4336"
+	tests.Check(c.Run(c.Cmd(), nil))
+	r.Compare(t, `PRODUCT   QTY  ORDERS  TOTAL
+14SCREEN  2    1       25.50
+total                  25.50
+`)
+	r.ClearBuf()
+	c.store = ""
+
+	c.by = "store"
+	r.Conf.Budget.Monthly = "100"
+	tests.Check(c.Run(c.Cmd(), nil))
+	if !r.Contains("4336   1       25.50") || !r.Contains("monthly budget is left") {
+		t.Errorf("wrong store totals:\n%s", r.Out.String())
+	}
+	r.Conf.Budget.Monthly = ""
+
+	c.by = "year"
+	tests.Exp(c.Run(c.Cmd(), nil), "should not group by year")
+}
@@ -1,47 +1,11 @@
 package dawg
 
 import (
-	"errors"
 	"fmt"
-	"regexp"
 	"strconv"
 	"strings"
 )
 
-var (
-	addressRegex = regexp.MustCompile(
-		fmt.Sprintf(`^(?P<%s>[0-9]{1,4})\s(?P<%s>[A-Za-z ]+\.|\n).?\n?\s*(?P<%s>[A-Za-z ]+),\s(?P<%s>[A-Z]{2})\s(?P<%s>[0-9]{5}).*$`,
-			"street_num", "street", "city", "state", "zipcode"),
-	)
-)
-
-// ParseAddress will parse a raw address and return an address object.
-// This method is prone to all the mishaps that arise when trying to work
-// with addresses, it may not work for all cases.
-func ParseAddress(raw string) (*StreetAddr, error) {
-	parsed, err := parse([]byte(raw))
-	if err != nil {
-		return nil, err
-	}
-	addr := &StreetAddr{
-		StreetNum:  string(parsed[1]),
-		Street:     string(parsed[1]) + " " + string(parsed[2]),
-		StreetName: string(parsed[2]),
-		CityName:   string(parsed[3]),
-		State:      string(parsed[4]),
-		Zipcode:    string(parsed[5]),
-	}
-	return addr, nil
-}
-
-func parse(raw []byte) ([][]byte, error) {
-	res := addressRegex.FindAllSubmatch(raw, -1)
-	if len(res) > 0 {
-		return res[0], nil
-	}
-	return nil, errors.New("could not parse address string")
-}
-
 // Address is a guid for how addresses should be used as input
 type Address interface {
 	LineOne() string
@@ -70,8 +34,29 @@ type StreetAddr struct {
 	// This is a dominos specific field, and should one of the following...
 	// "House", "Apartment", "Business", "Campus/Base", "Hotel", or "Other"
 	AddrType string `json:"Type"`
+
+	// UnitType and UnitNumber are for addresses inside of a building, like
+	// "Apartment" and "4B".
+	UnitType   string `json:"UnitType,omitempty"`
+	UnitNumber string `json:"UnitNumber,omitempty"`
+
+	// DeliveryInstructions are notes for the delivery driver.
+	DeliveryInstructions string `json:"DeliveryInstructions,omitempty"`
+}
+
+// DetailedAddress is an Address with the extra details that a delivery
+// driver needs. The details are copied when converting between address types
+// (see StreetAddrFromAddress).
+type DetailedAddress interface {
+	Address
+	// Unit returns the unit type and number, like "Apartment" and "4B".
+	Unit() (unitType, unitNumber string)
+	// Instructions returns the notes for the delivery driver.
+	Instructions() string
 }
 
+var _ DetailedAddress = (*StreetAddr)(nil)
+
 // StreetAddrFromAddress returns a StreetAddr pointer from an Address interface.
 func StreetAddrFromAddress(addr Address) *StreetAddr {
 	parts := strings.Split(addr.LineOne(), " ")
@@ -92,7 +77,7 @@ func StreetAddrFromAddress(addr Address) *StreetAddr {
 		return res
 	}
 
-	return &StreetAddr{
+	res := &StreetAddr{
 		Street:     addr.LineOne(),
 		StreetNum:  streetNum,
 		CityName:   addr.City(),
@@ -100,6 +85,11 @@ func StreetAddrFromAddress(addr Address) *StreetAddr {
 		Zipcode:    addr.Zip(),
 		StreetName: streetName,
 	}
+	if details, ok := addr.(DetailedAddress); ok {
+		res.UnitType, res.UnitNumber = details.Unit()
+		res.DeliveryInstructions = details.Instructions()
+	}
+	return res
 }
 
 // LineOne gives the street in the following format
@@ -127,3 +117,13 @@ func (s *StreetAddr) StateCode() string {
 func (s *StreetAddr) City() string {
 	return s.CityName
 }
+
+// Unit returns the unit type and number of the address.
+func (s *StreetAddr) Unit() (unitType, unitNumber string) {
+	return s.UnitType, s.UnitNumber
+}
+
+// Instructions returns the delivery instructions for the address.
+func (s *StreetAddr) Instructions() string {
+	return s.DeliveryInstructions
+}
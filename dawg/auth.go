@@ -31,15 +31,9 @@ var (
 		Host:   "api.dominos.com",
 		Path:   "/as/token.oauth2",
 	}
-
-	loginURL = &url.URL{
-		Scheme: "https",
-		Host:   orderHost,
-		Path:   "/power/login",
-	}
 )
 
-func newauth(username, password string) (*auth, error) {
+func newauth(m *Market, username, password string) (*auth, error) {
 	tok, err := gettoken(username, password)
 	if err != nil {
 		return nil, err
@@ -49,7 +43,8 @@ func newauth(username, password string) (*auth, error) {
 		username: username,
 		password: password,
 		cli: &client{
-			host: orderHost,
+			host:   m.Host,
+			market: m,
 			Client: &http.Client{
 				Transport:     tok,
 				Timeout:       60 * time.Second,
@@ -83,6 +78,9 @@ func (t *token) authorization() string {
 }
 
 func (t *token) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := checkOffline(); err != nil {
+		return nil, err
+	}
 	req.Header.Add("Authorization", t.authorization())
 	return t.transport.RoundTrip(req)
 }
@@ -106,6 +104,9 @@ var scopes = []string{
 }
 
 func gettoken(username, password string) (*token, error) {
+	if err := checkOffline(); err != nil {
+		return nil, err
+	}
 	data := url.Values{
 		"grant_type": {"password"},
 		"client_id":  {"nolo-rm"}, // nolo-rm if you want a refresh token, or just nolo for temporary token
@@ -133,7 +134,11 @@ func (a *auth) login() (*UserProfile, error) {
 		"u":               {a.username},
 		"p":               {a.password},
 	}
-	req := newAuthRequest(loginURL, data)
+	req := newAuthRequest(&url.URL{
+		Scheme: "https",
+		Host:   a.cli.host,
+		Path:   "/power/login",
+	}, data)
 	res, err := a.cli.Do(req)
 	if err != nil {
 		return nil, err
@@ -168,10 +173,14 @@ func newAuthRequest(u *url.URL, vals url.Values) *http.Request {
 
 type client struct {
 	*http.Client
-	host string
+	host   string
+	market *Market
 }
 
 func (c *client) do(req *http.Request) ([]byte, error) {
+	if err := checkOffline(); err != nil {
+		return nil, err
+	}
 	var buf bytes.Buffer
 	resp, err := c.Do(req)
 	if err != nil {
@@ -183,13 +192,16 @@ func (c *client) do(req *http.Request) ([]byte, error) {
 		return nil, fmt.Errorf("dawg.client.do: bad status code %d", resp.StatusCode)
 	}
 	_, err = buf.ReadFrom(resp.Body)
-	if bytes.HasPrefix(bytes.ToLower(buf.Bytes()[:15]), []byte("<!doctype html>")) {
+	if buf.Len() >= 15 && bytes.HasPrefix(bytes.ToLower(buf.Bytes()[:15]), []byte("<!doctype html>")) {
 		return nil, errpair(err, errors.New("got html response"))
 	}
 	return buf.Bytes(), err
 }
 
 func (c *client) dojson(v interface{}, r *http.Request) (err error) {
+	if err = checkOffline(); err != nil {
+		return err
+	}
 	resp, err := c.Do(r)
 	if err != nil {
 		return err
@@ -56,7 +56,7 @@ var (
 func getTestAuth(uname, pass string) (*auth, error) {
 	var err error
 	if testAuth == nil {
-		testAuth, err = newauth(uname, pass)
+		testAuth, err = newauth(UnitedStates, uname, pass)
 	}
 	return testAuth, err
 }
@@ -231,7 +231,7 @@ func TestAuth(t *testing.T) {
 func TestAuth_Err(t *testing.T) {
 	defer swapclient(2)()
 	tests.InitHelpers(t)
-	a, err := newauth("not a", "valid password")
+	a, err := newauth(UnitedStates, "not a", "valid password")
 	tests.Exp(err)
 	if a != nil {
 		t.Error("expected a nil auth")
@@ -0,0 +1,144 @@
+package dawg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SkipCategory can be returned by a MenuWalkFunc to skip the sub-categories
+// of the category it was called with.
+var SkipCategory = errors.New("skip this category")
+
+// MenuWalkFunc is called for each category in Menu.Walk. The parents are the
+// categories above the category, starting from the top of the menu.
+type MenuWalkFunc func(cat MenuCategory, parents []MenuCategory) error
+
+// Walk calls fn for every category on the menu, including the pre-configured
+// products, parents before their sub-categories. If fn returns SkipCategory
+// the sub-categories are skipped, and any other error stops the walk and is
+// returned.
+func (m *Menu) Walk(fn MenuWalkFunc) error {
+	for _, root := range []MenuCategory{m.Categorization.Food, m.Categorization.Preconfigured} {
+		for _, cat := range root.Categories {
+			if err := walkCategory(cat, nil, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func walkCategory(cat MenuCategory, parents []MenuCategory, fn MenuWalkFunc) error {
+	err := fn(cat, parents)
+	if err == SkipCategory {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	parents = append(parents[:len(parents):len(parents)], cat)
+	for _, sub := range cat.Categories {
+		if err = walkCategory(sub, parents, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Category finds a category by its path of names or codes, like
+// Category("pizza", "specialty") or Category("pizza/specialty"). Names and
+// codes are not case sensitive. The path does not have to start at the top of
+// the menu, so Category("specialty") also works, and the category closest to
+// the top is used when more than one matches.
+func (m *Menu) Category(path ...string) (MenuCategory, error) {
+	return findCategory([]MenuCategory{m.Categorization.Food, m.Categorization.Preconfigured}, path)
+}
+
+// Find finds one of the category's sub-categories by its path in the same way
+// as Menu.Category, so that a search can be limited to one part of the menu.
+func (m MenuCategory) Find(path ...string) (MenuCategory, error) {
+	return findCategory([]MenuCategory{m}, path)
+}
+
+func findCategory(roots []MenuCategory, path []string) (MenuCategory, error) {
+	var parts []string
+	for _, p := range path {
+		for _, s := range strings.Split(p, "/") {
+			if s = strings.TrimSpace(s); s != "" {
+				parts = append(parts, s)
+			}
+		}
+	}
+	if len(parts) == 0 {
+		return MenuCategory{}, errors.New("no category given")
+	}
+
+	var (
+		found MenuCategory
+		depth = -1
+	)
+	fn := func(cat MenuCategory, parents []MenuCategory) error {
+		if depth >= 0 && len(parents) >= depth {
+			return SkipCategory // cannot be any closer to the top
+		}
+		if c, ok := subCategory(cat, parts); ok {
+			found, depth = c, len(parents)
+			return SkipCategory
+		}
+		return nil
+	}
+	for _, root := range roots {
+		for _, cat := range root.Categories {
+			walkCategory(cat, nil, fn)
+		}
+	}
+	if depth < 0 {
+		return MenuCategory{}, fmt.Errorf("could not find the %s category", strings.Join(parts, "/"))
+	}
+	return found, nil
+}
+
+// subCategory follows a path down from a category. The first part of the
+// path has to match the category itself.
+func subCategory(cat MenuCategory, path []string) (MenuCategory, bool) {
+	if !cat.matches(path[0]) {
+		return MenuCategory{}, false
+	}
+	if len(path) == 1 {
+		return cat, true
+	}
+	for _, sub := range cat.Categories {
+		if c, ok := subCategory(sub, path[1:]); ok {
+			return c, true
+		}
+	}
+	return MenuCategory{}, false
+}
+
+func (m MenuCategory) matches(s string) bool {
+	return (m.Code != "" && strings.EqualFold(m.Code, s)) ||
+		(m.Name != "" && strings.EqualFold(m.Name, s))
+}
+
+// Items finds the items in a category and all of its sub-categories in the
+// order that they are on the menu. Items listed more than once are only
+// included the first time and codes that are not on the menu are left out.
+func (m *Menu) Items(cat MenuCategory) []Item {
+	var (
+		items []Item
+		seen  = make(map[string]bool)
+	)
+	walkCategory(cat, nil, func(c MenuCategory, _ []MenuCategory) error {
+		for _, code := range c.Products {
+			if seen[code] {
+				continue
+			}
+			seen[code] = true
+			if itm := m.FindItem(code); itm != nil {
+				items = append(items, itm)
+			}
+		}
+		return nil
+	})
+	return items
+}
@@ -0,0 +1,121 @@
+package dawg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func testCategoryMenu() *Menu {
+	m := testSearchMenu()
+	m.Preconfigured = map[string]*PreConfiguredProduct{
+		"14SCEXTRAV": {ItemCommon: ItemCommon{Code: "14SCEXTRAV", Name: "Large ExtravaganZZa"}},
+	}
+	m.Categorization.Food = MenuCategory{Categories: []MenuCategory{
+		{Name: "Pizza", Code: "Pizza", Categories: []MenuCategory{
+			{Name: "Build Your Own", Code: "BuildYourOwn", Products: []string{"S_PIZZA"}},
+			{Name: "Specialty Pizzas", Code: "Specialty", Products: []string{"S_PEPPER", "S_PIZZA", "NOPE"}},
+		}},
+		{Name: "Bread", Code: "Bread", Products: []string{"S_BREAD"}},
+	}}
+	m.Categorization.Preconfigured = MenuCategory{Categories: []MenuCategory{
+		{Name: "Popular Items", Code: "PopularItems", Products: []string{"14SCEXTRAV"}},
+	}}
+	return m
+}
+
+func TestMenuWalk(t *testing.T) {
+	tests.InitHelpers(t)
+	m := testCategoryMenu()
+	var paths []string
+	tests.Check(m.Walk(func(cat MenuCategory, parents []MenuCategory) error {
+		var names []string
+		for _, p := range parents {
+			names = append(names, p.Code)
+		}
+		paths = append(paths, strings.Join(append(names, cat.Code), "/"))
+		return nil
+	}))
+	tests.StrEq(strings.Join(paths, ","), "Pizza,Pizza/BuildYourOwn,Pizza/Specialty,Bread,PopularItems", "wrong walk order")
+
+	paths = nil
+	tests.Check(m.Walk(func(cat MenuCategory, parents []MenuCategory) error {
+		paths = append(paths, cat.Code)
+		if cat.Code == "Pizza" {
+			return SkipCategory
+		}
+		return nil
+	}))
+	tests.StrEq(strings.Join(paths, ","), "Pizza,Bread,PopularItems", "sub-categories should be skipped")
+
+	stop := errors.New("stop")
+	paths = nil
+	err := m.Walk(func(cat MenuCategory, parents []MenuCategory) error {
+		paths = append(paths, cat.Code)
+		return stop
+	})
+	if err != stop || len(paths) != 1 {
+		t.Error("walk should stop at the first error")
+	}
+}
+
+func TestMenuCategory(t *testing.T) {
+	tests.InitHelpers(t)
+	m := testCategoryMenu()
+	for _, tc := range []struct {
+		path []string
+		code string
+	}{
+		{[]string{"pizza"}, "Pizza"},
+		{[]string{"pizza/specialty"}, "Specialty"},
+		{[]string{"Pizza", "Specialty Pizzas"}, "Specialty"},
+		{[]string{"specialty"}, "Specialty"},
+		{[]string{"build your own"}, "BuildYourOwn"},
+		{[]string{"popularitems"}, "PopularItems"},
+		{[]string{"/bread/"}, "Bread"},
+	} {
+		cat, err := m.Category(tc.path...)
+		if err != nil {
+			t.Errorf("%v: %v", tc.path, err)
+			continue
+		}
+		tests.StrEq(cat.Code, tc.code, "wrong category for %v", tc.path)
+	}
+	for _, bad := range [][]string{{"pizza/bread"}, {"wings"}, {}, {"/"}} {
+		_, err := m.Category(bad...)
+		tests.Exp(err, "should not find", bad)
+	}
+
+	cat, err := m.Categorization.Food.Find("specialty")
+	tests.Check(err)
+	tests.StrEq(cat.Code, "Specialty", "wrong category")
+	_, err = m.Categorization.Food.Find("popularitems")
+	tests.Exp(err, "pre-configured categories are not in the food categories")
+	cat, err = m.Categorization.Preconfigured.Find("popularitems")
+	tests.Check(err)
+	tests.StrEq(cat.Code, "PopularItems", "wrong category")
+}
+
+func TestMenuItems(t *testing.T) {
+	tests.InitHelpers(t)
+	m := testCategoryMenu()
+	cat, err := m.Category("pizza")
+	tests.Check(err)
+	var codes []string
+	for _, itm := range m.Items(cat) {
+		codes = append(codes, itm.ItemCode())
+	}
+	tests.StrEq(strings.Join(codes, ","), "S_PIZZA,S_PEPPER", "wrong items")
+
+	cat, err = m.Category("popularitems")
+	tests.Check(err)
+	items := m.Items(cat)
+	if len(items) != 1 {
+		t.Fatalf("got %d items, want 1", len(items))
+	}
+	if _, ok := items[0].(*PreConfiguredProduct); !ok {
+		t.Errorf("wrong item type %T", items[0])
+	}
+}
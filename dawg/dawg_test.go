@@ -41,6 +41,11 @@ func TestOrderAddressConvertion(t *testing.T) {
 	tests.StrEq(res.Zip(), exp.Zip(), "wrong zip code")
 	tests.StrEq(res.StreetNum, exp.StreetNum, "wrong street number")
 	tests.StrEq(res.StreetName, exp.StreetName, "wrong street name")
+
+	addr.SetStreet("221 Baker St")
+	tests.StrEq(addr.LineOne(), "221 Baker St", "wrong lineone")
+	tests.StrEq(addr.StreetNumber, "221", "street number should be split again")
+	tests.StrEq(addr.StreetName, "Baker St", "street name should be split again")
 }
 
 func TestParseAddressTable(t *testing.T) {
@@ -51,26 +56,106 @@ func TestParseAddressTable(t *testing.T) {
 	}{
 		{
 			raw: `1600 Pennsylvania Ave. Washington, DC 20500`,
-			expected: StreetAddr{StreetNum: "1600", StreetName: "Pennsylvania Ave.",
-				Street: "1600 Pennsylvania Ave.", CityName: "Washington",
-				State: "DC", Zipcode: "20500", AddrType: "House"},
+			expected: StreetAddr{StreetNum: "1600", StreetName: "Pennsylvania Ave",
+				Street: "1600 Pennsylvania Ave", CityName: "Washington",
+				State: "DC", Zipcode: "20500"},
 		},
 		{
 			raw: `378 James St. Chicago, IL 60621`,
-			expected: StreetAddr{StreetNum: "378", StreetName: "James St.",
-				Street: "378 James St.", CityName: "Chicago", State: "IL",
+			expected: StreetAddr{StreetNum: "378", StreetName: "James St",
+				Street: "378 James St", CityName: "Chicago", State: "IL",
 				Zipcode: "60621"},
 		},
+		{
+			raw: `100 Queen St. Toronto, ON M5H 2N2`,
+			expected: StreetAddr{StreetNum: "100", StreetName: "Queen St",
+				Street: "100 Queen St", CityName: "Toronto", State: "ON",
+				Zipcode: "M5H 2N2"},
+		},
+		{
+			raw: `123 Main St Apt 4, Springfield, IL 62704-1234`,
+			expected: StreetAddr{StreetNum: "123", StreetName: "Main St",
+				Street: "123 Main St", CityName: "Springfield", State: "IL",
+				Zipcode: "62704-1234", UnitType: "Apartment", UnitNumber: "4", AddrType: "Apartment"},
+		},
+		{
+			raw: `123 north main street #4b springfield il 627041234`,
+			expected: StreetAddr{StreetNum: "123", StreetName: "N main St",
+				Street: "123 N main St", CityName: "springfield", State: "IL",
+				Zipcode: "62704-1234", UnitNumber: "4b"},
+		},
+		{
+			raw: "1600 Pennsylvania Avenue Northwest\nSuite 100\nWashington, District of Columbia 20500\nUSA",
+			expected: StreetAddr{StreetNum: "1600", StreetName: "Pennsylvania Ave NW",
+				Street: "1600 Pennsylvania Ave NW", CityName: "Washington", State: "DC",
+				Zipcode: "20500", UnitType: "Suite", UnitNumber: "100", AddrType: "Business"},
+		},
+		{
+			raw: `45 Court St Brooklyn NY 11201`,
+			expected: StreetAddr{StreetNum: "45", StreetName: "Court St",
+				Street: "45 Court St", CityName: "Brooklyn", State: "NY", Zipcode: "11201"},
+		},
+		{
+			raw: `12 North St West Palm Beach FL 33401`,
+			expected: StreetAddr{StreetNum: "12", StreetName: "North St",
+				Street: "12 North St", CityName: "West Palm Beach", State: "FL", Zipcode: "33401"},
+		},
+		{
+			raw: `350 5th Ave Fl 34, New York, NY 10118`,
+			expected: StreetAddr{StreetNum: "350", StreetName: "5th Ave",
+				Street: "350 5th Ave", CityName: "New York", State: "NY", Zipcode: "10118",
+				UnitType: "Floor", UnitNumber: "34", AddrType: "Business"},
+		},
+		{
+			raw: `1 Broadway, Cambridge, Massachusetts 02142`,
+			expected: StreetAddr{StreetNum: "1", StreetName: "Broadway",
+				Street: "1 Broadway", CityName: "Cambridge", State: "MA", Zipcode: "02142"},
+		},
+		{
+			raw: `290 Bremner Blvd, Toronto, Ontario m5v3l9, Canada`,
+			expected: StreetAddr{StreetNum: "290", StreetName: "Bremner Blvd",
+				Street: "290 Bremner Blvd", CityName: "Toronto", State: "ON", Zipcode: "M5V 3L9"},
+		},
 	}
 
 	for _, tc := range cases {
 		addr, err := ParseAddress(tc.raw)
-		tests.Check(err)
-		tests.StrEq(addr.StreetNum, tc.expected.StreetNum, "wrong street num")
-		tests.StrEq(addr.Street, tc.expected.Street, "wrong street")
-		tests.StrEq(addr.CityName, tc.expected.CityName, "wrong city")
-		tests.StrEq(addr.State, tc.expected.State, "wrong state")
-		tests.StrEq(addr.Zipcode, tc.expected.Zipcode, "wrong zip")
+		if err != nil {
+			t.Errorf("%q: %v", tc.raw, err)
+			continue
+		}
+		exp := tc.expected
+		tests.StrEq(addr.StreetNum, exp.StreetNum, "%q: wrong street num", tc.raw)
+		tests.StrEq(addr.StreetName, exp.StreetName, "%q: wrong street name", tc.raw)
+		tests.StrEq(addr.Street, exp.Street, "%q: wrong street", tc.raw)
+		tests.StrEq(addr.CityName, exp.CityName, "%q: wrong city", tc.raw)
+		tests.StrEq(addr.State, exp.State, "%q: wrong state", tc.raw)
+		tests.StrEq(addr.Zipcode, exp.Zipcode, "%q: wrong zip", tc.raw)
+		tests.StrEq(addr.UnitType, exp.UnitType, "%q: wrong unit type", tc.raw)
+		tests.StrEq(addr.UnitNumber, exp.UnitNumber, "%q: wrong unit number", tc.raw)
+		tests.StrEq(addr.AddrType, exp.AddrType, "%q: wrong address type", tc.raw)
+	}
+}
+
+func TestParseAddress_Errors(t *testing.T) {
+	for raw, field := range map[string]string{
+		"":                                       "address",
+		"123 Main St, Springfield, IL":           "postal code",
+		"123 Main St, Springfield, XX 62704":     "region",
+		"Main St, Springfield, IL 62704":         "street number",
+		"123, Springfield, IL 62704":             "street",
+		"123 Main St Apt, Springfield, IL 62704": "unit",
+		"123 Main St, IL 62704":                  "city",
+	} {
+		_, err := ParseAddress(raw)
+		e, ok := err.(*AddressError)
+		if !ok {
+			t.Errorf("%q: expected an *AddressError, got %v", raw, err)
+			continue
+		}
+		if e.Field != field {
+			t.Errorf("%q: got an error for the %s, want the %s: %v", raw, e.Field, field, e)
+		}
 	}
 }
 
@@ -233,7 +318,7 @@ var (
 
 func testingStore() *Store {
 	var (
-		service string
+		service ServiceMethod
 		err     error
 	)
 
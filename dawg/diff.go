@@ -0,0 +1,122 @@
+package dawg
+
+import "sort"
+
+// MenuDiff is the list of changes between two versions of a menu.
+type MenuDiff struct {
+	AddedProducts   []DiffItem
+	RemovedProducts []DiffItem
+	AddedVariants   []DiffItem
+	RemovedVariants []DiffItem
+	AddedToppings   []DiffTopping
+	RemovedToppings []DiffTopping
+
+	// PriceChanges are the variants that are on both menus but have a
+	// different price.
+	PriceChanges []PriceChange
+}
+
+// DiffItem is a product or variant that was added or removed from the menu.
+type DiffItem struct {
+	Code string
+	Name string
+}
+
+// DiffTopping is a topping that was added or removed from the menu.
+type DiffTopping struct {
+	ProductType string
+	Code        string
+	Name        string
+}
+
+// PriceChange is a variant with a new price.
+type PriceChange struct {
+	Code     string
+	Name     string
+	OldPrice Money
+	NewPrice Money
+}
+
+// DiffMenus finds the differences between an old and a new menu. A nil menu
+// is treated like an empty one.
+func DiffMenus(old, new *Menu) *MenuDiff {
+	if old == nil {
+		old = &Menu{}
+	}
+	if new == nil {
+		new = &Menu{}
+	}
+	d := &MenuDiff{}
+
+	for code, p := range new.Products {
+		if _, ok := old.Products[code]; !ok {
+			d.AddedProducts = append(d.AddedProducts, DiffItem{code, p.Name})
+		}
+	}
+	for code, p := range old.Products {
+		if _, ok := new.Products[code]; !ok {
+			d.RemovedProducts = append(d.RemovedProducts, DiffItem{code, p.Name})
+		}
+	}
+
+	for code, v := range new.Variants {
+		oldv, ok := old.Variants[code]
+		if !ok {
+			d.AddedVariants = append(d.AddedVariants, DiffItem{code, v.Name})
+		} else if oldv.Price.Units != v.Price.Units {
+			d.PriceChanges = append(d.PriceChanges, PriceChange{
+				Code:     code,
+				Name:     v.Name,
+				OldPrice: oldv.Price,
+				NewPrice: v.Price,
+			})
+		}
+	}
+	for code, v := range old.Variants {
+		if _, ok := new.Variants[code]; !ok {
+			d.RemovedVariants = append(d.RemovedVariants, DiffItem{code, v.Name})
+		}
+	}
+
+	d.AddedToppings = toppingDiff(new.Toppings, old.Toppings)
+	d.RemovedToppings = toppingDiff(old.Toppings, new.Toppings)
+
+	sortDiffItems(d.AddedProducts)
+	sortDiffItems(d.RemovedProducts)
+	sortDiffItems(d.AddedVariants)
+	sortDiffItems(d.RemovedVariants)
+	sort.Slice(d.PriceChanges, func(i, j int) bool {
+		return d.PriceChanges[i].Code < d.PriceChanges[j].Code
+	})
+	return d
+}
+
+// Empty returns true if there are no changes.
+func (d *MenuDiff) Empty() bool {
+	return len(d.AddedProducts) == 0 && len(d.RemovedProducts) == 0 &&
+		len(d.AddedVariants) == 0 && len(d.RemovedVariants) == 0 &&
+		len(d.AddedToppings) == 0 && len(d.RemovedToppings) == 0 &&
+		len(d.PriceChanges) == 0
+}
+
+// toppingDiff finds the toppings in a that are not in b.
+func toppingDiff(a, b map[string]map[string]Topping) (diff []DiffTopping) {
+	for typ, toppings := range a {
+		for code, t := range toppings {
+			if _, ok := b[typ][code]; !ok {
+				diff = append(diff, DiffTopping{ProductType: typ, Code: code, Name: t.Name})
+			}
+		}
+	}
+	sort.Slice(diff, func(i, j int) bool {
+		if diff[i].ProductType != diff[j].ProductType {
+			return diff[i].ProductType < diff[j].ProductType
+		}
+		return diff[i].Code < diff[j].Code
+	})
+	return diff
+}
+
+func sortDiffItems(items []DiffItem) {
+	sort.Slice(items, func(i, j int) bool { return items[i].Code < items[j].Code })
+}
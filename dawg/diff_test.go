@@ -0,0 +1,56 @@
+package dawg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffMenus(t *testing.T) {
+	old := testSearchMenu()
+	old.Toppings = map[string]map[string]Topping{
+		"Pizza": {
+			"P": {ItemCommon: ItemCommon{Code: "P", Name: "Pepperoni"}},
+			"X": {ItemCommon: ItemCommon{Code: "X", Name: "Sauce"}},
+		},
+	}
+	new := testSearchMenu()
+	new.Toppings = map[string]map[string]Topping{
+		"Pizza": {"P": {ItemCommon: ItemCommon{Code: "P", Name: "Pepperoni"}}},
+		"Wings": {"H": {ItemCommon: ItemCommon{Code: "H", Name: "Hot Sauce"}}},
+	}
+
+	if d := DiffMenus(old, new); len(d.PriceChanges) != 0 || len(d.AddedProducts) != 0 {
+		t.Error("same products should not have changes")
+	}
+
+	delete(new.Products, "S_PEPPER")
+	delete(new.Variants, "14SCPEPPER")
+	new.Products["S_WINGS"] = &Product{ItemCommon: ItemCommon{Code: "S_WINGS", Name: "Wings"}}
+	new.Variants["W08PHOTW"] = &Variant{ItemCommon: ItemCommon{Code: "W08PHOTW", Name: "8 Hot Wings"}, Price: Cents(799)}
+	new.Variants["12SCREEN"] = &Variant{ItemCommon: ItemCommon{Code: "12SCREEN", Name: "Medium Hand Tossed"}, Price: Cents(1049)}
+
+	d := DiffMenus(old, new)
+	if d.Empty() {
+		t.Fatal("diff should not be empty")
+	}
+	exp := &MenuDiff{
+		AddedProducts:   []DiffItem{{"S_WINGS", "Wings"}},
+		RemovedProducts: []DiffItem{{"S_PEPPER", "Pepperoni Feast"}},
+		AddedVariants:   []DiffItem{{"W08PHOTW", "8 Hot Wings"}},
+		RemovedVariants: []DiffItem{{"14SCPEPPER", "Large (14\") Pepperoni Feast"}},
+		AddedToppings:   []DiffTopping{{"Wings", "H", "Hot Sauce"}},
+		RemovedToppings: []DiffTopping{{"Pizza", "X", "Sauce"}},
+		PriceChanges:    []PriceChange{{"12SCREEN", "Medium Hand Tossed", Cents(999), Cents(1049)}},
+	}
+	if !reflect.DeepEqual(d, exp) {
+		t.Errorf("wrong diff:\ngot  %+v\nwant %+v", d, exp)
+	}
+
+	d = DiffMenus(nil, new)
+	if len(d.AddedProducts) != len(new.Products) || len(d.RemovedProducts) != 0 {
+		t.Error("everything should be added when diffing against a nil menu")
+	}
+	if !DiffMenus(nil, nil).Empty() {
+		t.Error("two nil menus should be the same")
+	}
+}
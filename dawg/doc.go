@@ -15,6 +15,10 @@
 // 		// handle error
 // 	}
 //
+// Both functions use the dominos market in the United States. Stores and
+// accounts in other countries are found through that country's Market.
+// 	store, err := dawg.Canada.NearestStore(&address, dawg.Carryout)
+//
 // To order anything from dominos you need to find a store, create an order,
 // then send that order.
 package dawg
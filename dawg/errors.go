@@ -22,7 +22,7 @@ const (
 
 var (
 	// ErrBadService is returned if a service is needed but the service validation failed.
-	ErrBadService = errors.New("service must be either 'Delivery' or 'Carryout'")
+	ErrBadService = errors.New("service must be one of 'Delivery', 'Carryout', 'DriveUpCarryout', or 'DineIn'")
 
 	// ErrNoUserService is thrown when a user has no service method.
 	ErrNoUserService = errors.New("UserProfile has no service method (use user.SetServiceMethod)")
@@ -0,0 +1,114 @@
+package dawg
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// FutureTimeFormat is the format dominos uses for an order's FutureOrderTime.
+const FutureTimeFormat = "2006-01-02 15:04:05"
+
+// the format of the open and close times in StoreHours
+const storeHoursFormat = "15:04"
+
+// IsOpen tells whether the store hours include the time t. Store hours are
+// given in the store's local time, so t should be in the store's time zone.
+//
+// Hours that close at or before they open, like 10:00 to 01:00, are taken to
+// close on the next day.
+func (h *StoreHours) IsOpen(t time.Time) bool {
+	// the hours from the day before can run past midnight
+	for _, day := range []time.Time{t.AddDate(0, 0, -1), t} {
+		for _, span := range h.weekday(day.Weekday()) {
+			open, close, err := hoursSpan(day, span.OpenTime, span.CloseTime)
+			if err != nil {
+				continue
+			}
+			if !t.Before(open) && t.Before(close) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (h *StoreHours) weekday(d time.Weekday) []struct {
+	OpenTime  string
+	CloseTime string
+} {
+	switch d {
+	case time.Sunday:
+		return h.Sun
+	case time.Monday:
+		return h.Mon
+	case time.Tuesday:
+		return h.Tue
+	case time.Wednesday:
+		return h.Wed
+	case time.Thursday:
+		return h.Thu
+	case time.Friday:
+		return h.Fri
+	default:
+		return h.Sat
+	}
+}
+
+func (h *StoreHours) empty() bool {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if len(h.weekday(d)) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func hoursSpan(day time.Time, openTime, closeTime string) (open, close time.Time, err error) {
+	o, err := time.Parse(storeHoursFormat, openTime)
+	if err != nil {
+		return
+	}
+	c, err := time.Parse(storeHoursFormat, closeTime)
+	if err != nil {
+		return
+	}
+	y, m, d := day.Date()
+	open = time.Date(y, m, d, o.Hour(), o.Minute(), 0, 0, day.Location())
+	close = time.Date(y, m, d, c.Hour(), c.Minute(), 0, 0, day.Location())
+	if !close.After(open) {
+		close = close.AddDate(0, 0, 1)
+	}
+	return open, close, nil
+}
+
+// ValidateFutureTime returns an error if an order for the service method
+// cannot be scheduled for the time t (see CheckHours).
+func (s *Store) ValidateFutureTime(service ServiceMethod, t time.Time) error {
+	if !t.After(time.Now()) {
+		return errors.New("future order time must be in the future")
+	}
+	return s.CheckHours(service, t)
+}
+
+// CheckHours returns an error if the store is not open for the service method
+// at the time t. The store's service hours for the service method are used,
+// falling back on the carryout hours for other kinds of carryout and then on
+// the store's hours.
+func (s *Store) CheckHours(service ServiceMethod, t time.Time) error {
+	hours := s.ServiceHours[string(service)]
+	if hours.empty() && service.IsCarryout() {
+		hours = s.ServiceHours[string(Carryout)]
+	}
+	if hours.empty() {
+		hours = s.Hours
+	}
+	if hours.empty() {
+		return fmt.Errorf("store %s has no hours for %s", s.ID, service)
+	}
+	if !hours.IsOpen(t) {
+		return fmt.Errorf("store %s is not open for %s at %s",
+			s.ID, service, t.Format("Mon Jan 2 3:04 PM"))
+	}
+	return nil
+}
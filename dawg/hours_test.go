@@ -0,0 +1,91 @@
+package dawg
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func testHours(open, close string) StoreHours {
+	h := StoreHours{}
+	day := []struct {
+		OpenTime  string
+		CloseTime string
+	}{{OpenTime: open, CloseTime: close}}
+	h.Sun, h.Mon, h.Tue, h.Wed, h.Thu, h.Fri, h.Sat = day, day, day, day, day, day, day
+	return h
+}
+
+func TestStoreHours_IsOpen(t *testing.T) {
+	h := testHours("10:30", "01:00")
+	h.Sun = nil
+	sat := time.Date(2026, time.October, 17, 0, 0, 0, 0, time.Local)
+	for _, tc := range []struct {
+		t    time.Time
+		open bool
+	}{
+		{sat.Add(10 * time.Hour), false},
+		{sat.Add(10*time.Hour + 30*time.Minute), true},
+		{sat.Add(23 * time.Hour), true},
+		{sat.Add(24*time.Hour + 30*time.Minute), true}, // sunday morning from saturday's hours
+		{sat.Add(25 * time.Hour), false},
+		{sat.Add(36 * time.Hour), false}, // closed on sunday
+		{sat.Add(48*time.Hour + 30*time.Minute), false},
+	} {
+		if h.IsOpen(tc.t) != tc.open {
+			t.Errorf("IsOpen(%s) should be %v", tc.t.Format(time.ANSIC), tc.open)
+		}
+	}
+	if (&StoreHours{}).IsOpen(sat.Add(12 * time.Hour)) {
+		t.Error("empty hours should never be open")
+	}
+}
+
+func TestOrder_SetFutureTime(t *testing.T) {
+	store := &Store{ID: "4336", Hours: testHours("10:00", "23:00"),
+		ServiceHours: map[string]StoreHours{string(Delivery): testHours("11:00", "22:00")}}
+	o := &Order{StoreID: "4336", ServiceMethod: Delivery}
+	y, m, d := time.Now().AddDate(0, 0, 2).Date()
+	lunch := time.Date(y, m, d, 12, 15, 0, 0, time.Local)
+
+	if err := o.SetFutureTime(store, lunch); err != nil {
+		t.Fatal(err)
+	}
+	if o.FutureOrderTime != lunch.Format("2006-01-02")+" 12:15:00" {
+		t.Errorf("wrong future order time %q", o.FutureOrderTime)
+	}
+	if !o.FutureTime().Equal(lunch) {
+		t.Error("FutureTime should give back the scheduled time")
+	}
+	if !strings.Contains(o.raw().String(), `"FutureOrderTime":"`+o.FutureOrderTime+`"`) {
+		t.Error("future order time should be sent to dominos")
+	}
+
+	early := lunch.Add(-90 * time.Minute)
+	if err := o.SetFutureTime(store, early); err == nil {
+		t.Error("delivery should not be open at 10:45")
+	}
+	o.ServiceMethod = Carryout
+	if err := o.SetFutureTime(store, early); err != nil {
+		t.Error("carryout should fall back on the store hours:", err)
+	}
+	if err := o.SetFutureTime(store, time.Now().Add(-time.Hour)); err == nil {
+		t.Error("should not schedule an order in the past")
+	}
+	if err := o.SetFutureTime(&Store{ID: "1111"}, lunch); err == nil {
+		t.Error("should not check the time against another store")
+	}
+	if err := o.SetFutureTime(&Store{ID: "4336"}, lunch); err == nil {
+		t.Error("should not schedule with a store that has no hours")
+	}
+
+	if err := o.SetFutureTime(nil, time.Time{}); err != nil {
+		t.Error(err)
+	}
+	if o.FutureOrderTime != "" || !o.FutureTime().IsZero() {
+		t.Error("a zero time should unschedule the order")
+	}
+	if strings.Contains(o.raw().String(), "FutureOrderTime") {
+		t.Error("orders made right away should not have a future order time")
+	}
+}
@@ -0,0 +1,259 @@
+package dawg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ItemKind tells which part of the menu an item code belongs to.
+type ItemKind int
+
+const (
+	// ProductItem is an item in Menu.Products.
+	ProductItem ItemKind = iota + 1
+	// VariantItem is an item in Menu.Variants.
+	VariantItem
+	// PreconfiguredItem is an item in Menu.Preconfigured.
+	PreconfiguredItem
+)
+
+// MenuIndex holds lookup tables for a Menu so that finding items by name or
+// category does not mean searching the whole menu. Indexes only store item
+// codes so they can be cached (with gob or json) next to the menu they came
+// from and given back to it with Menu.SetIndex.
+//
+// An index is made when a menu is decoded from dominos. It is not updated if
+// the menu is changed after that.
+type MenuIndex struct {
+	// MenuID is the ID of the menu that the index was made from.
+	MenuID string
+
+	// Hash is a hash of the parts of the menu that the index was made from.
+	// It changes when the store's menu does.
+	Hash string
+
+	// Kinds maps item codes to the part of the menu they are found in.
+	Kinds map[string]ItemKind
+
+	// ProductVariants maps product codes to the codes of their variants.
+	ProductVariants map[string][]string
+
+	// ToppingNames maps a product type and topping code to the topping name.
+	ToppingNames map[string]map[string]string
+
+	// Categories maps a category path (lowercase category codes joined with
+	// '/', like "pizza/specialty") to the codes of every item under it.
+	Categories map[string][]string
+
+	// CategoryNames maps category paths to the name of the category.
+	CategoryNames map[string]string
+
+	// ItemCategories maps item codes to the path of the category that they
+	// are listed under.
+	ItemCategories map[string]string
+
+	// Tokens maps the lowercase words in item names, descriptions, and tags
+	// to the codes of the items that have them.
+	Tokens map[string][]string
+}
+
+// NewMenuIndex will build the index of a menu.
+func NewMenuIndex(m *Menu) *MenuIndex {
+	idx := &MenuIndex{
+		MenuID:          m.ID,
+		Hash:            menuHash(m),
+		Kinds:           make(map[string]ItemKind),
+		ProductVariants: make(map[string][]string),
+		ToppingNames:    make(map[string]map[string]string),
+		Categories:      make(map[string][]string),
+		CategoryNames:   make(map[string]string),
+		ItemCategories:  make(map[string]string),
+		Tokens:          make(map[string][]string),
+	}
+	tokens := make(map[string]map[string]bool)
+	addTokens := func(code string, words []string) {
+		for _, w := range words {
+			if tokens[w] == nil {
+				tokens[w] = make(map[string]bool)
+			}
+			tokens[w][code] = true
+		}
+	}
+
+	for code, p := range m.Products {
+		idx.Kinds[code] = ProductItem
+		idx.ProductVariants[code] = append([]string(nil), p.Variants...)
+		addTokens(code, searchTokens(p.Name))
+		addTokens(code, searchTokens(p.Description))
+		addTokens(code, tagTokens(p.Tags))
+	}
+	for code, pc := range m.Preconfigured {
+		if _, ok := idx.Kinds[code]; !ok {
+			idx.Kinds[code] = PreconfiguredItem
+		}
+		addTokens(code, searchTokens(pc.Name))
+		addTokens(code, searchTokens(pc.Description))
+		addTokens(code, tagTokens(pc.Tags))
+	}
+	for code, v := range m.Variants {
+		if _, ok := idx.Kinds[code]; !ok {
+			idx.Kinds[code] = VariantItem
+		}
+		addTokens(code, searchTokens(v.Name))
+		addTokens(code, tagTokens(v.Tags))
+		if p, ok := m.Products[v.ProductCode]; ok {
+			addTokens(code, searchTokens(p.Description))
+			if !containsStr(p.Variants, code) {
+				idx.ProductVariants[p.Code] = append(idx.ProductVariants[p.Code], code)
+			}
+		}
+	}
+	for word, codes := range tokens {
+		idx.Tokens[word] = sortedKeys(codes)
+	}
+
+	for typ, toppings := range m.Toppings {
+		names := make(map[string]string, len(toppings))
+		for code, t := range toppings {
+			names[code] = t.Name
+		}
+		idx.ToppingNames[typ] = names
+	}
+
+	idx.addCategories(m.Categorization.Food.Categories, "")
+	idx.addCategories(m.Categorization.Preconfigured.Categories, "")
+	return idx
+}
+
+// addCategories adds a list of categories to the index and returns the codes
+// of all the items in them.
+func (idx *MenuIndex) addCategories(cats []MenuCategory, parent string) (all []string) {
+	for _, cat := range cats {
+		key := strings.ToLower(cat.Code)
+		if key == "" {
+			key = strings.ToLower(cat.Name)
+		}
+		path := key
+		if parent != "" {
+			path = parent + "/" + key
+		}
+		items := append([]string(nil), cat.Products...)
+		for _, code := range cat.Products {
+			if _, ok := idx.ItemCategories[code]; !ok {
+				idx.ItemCategories[code] = path
+			}
+		}
+		items = append(items, idx.addCategories(cat.Categories, path)...)
+
+		if _, ok := idx.Categories[path]; !ok {
+			idx.CategoryNames[path] = cat.Name
+		}
+		idx.Categories[path] = append(idx.Categories[path], items...)
+		all = append(all, items...)
+	}
+	return all
+}
+
+// Kind returns the kind of item that the code is for. Returns zero if the
+// code is not in the index.
+func (idx *MenuIndex) Kind(code string) ItemKind {
+	return idx.Kinds[code]
+}
+
+// Variants returns the variant codes for a product.
+func (idx *MenuIndex) Variants(product string) []string {
+	return idx.ProductVariants[product]
+}
+
+// ToppingName looks up the name of a topping for a product type.
+func (idx *MenuIndex) ToppingName(productType, code string) string {
+	return idx.ToppingNames[productType][code]
+}
+
+// Category returns the item codes in the category at path.
+func (idx *MenuIndex) Category(path string) []string {
+	return idx.Categories[strings.ToLower(path)]
+}
+
+// CategoryName gives the name of the category that an item is listed under.
+// Variants are listed under their product's category.
+func (idx *MenuIndex) CategoryName(code string) string {
+	path, ok := idx.ItemCategories[code]
+	if !ok {
+		return ""
+	}
+	return idx.CategoryNames[path]
+}
+
+// Lookup returns the codes of the items that have a word in their name,
+// description, or tags.
+func (idx *MenuIndex) Lookup(word string) []string {
+	return idx.Tokens[strings.ToLower(word)]
+}
+
+// Index returns the menu's index, building it if the menu does not have one
+// yet.
+func (m *Menu) Index() *MenuIndex {
+	if m.index == nil || m.index.MenuID != m.ID {
+		m.index = NewMenuIndex(m)
+	}
+	return m.index
+}
+
+// SetIndex gives the menu an index that was made earlier, usually one that
+// was cached with the menu. Indexes made from a different menu, including an
+// older menu from the same store, are ignored.
+func (m *Menu) SetIndex(idx *MenuIndex) {
+	if idx != nil && idx.MenuID == m.ID && idx.Kinds != nil && idx.Hash == menuHash(m) {
+		m.index = idx
+	}
+}
+
+// menuHash hashes everything on the menu that NewMenuIndex uses. Map values
+// are printed with fmt, which sorts map keys, and the lines are sorted so
+// the hash does not depend on map order.
+func menuHash(m *Menu) string {
+	var lines []string
+	for code, p := range m.Products {
+		lines = append(lines, fmt.Sprintf("product %s %q %q %v %v", code, p.Name, p.Description, p.Variants, p.Tags))
+	}
+	for code, pc := range m.Preconfigured {
+		lines = append(lines, fmt.Sprintf("preconfigured %s %q %q %v", code, pc.Name, pc.Description, pc.Tags))
+	}
+	for code, v := range m.Variants {
+		lines = append(lines, fmt.Sprintf("variant %s %q %s %v", code, v.Name, v.ProductCode, v.Tags))
+	}
+	for typ, toppings := range m.Toppings {
+		for code, t := range toppings {
+			lines = append(lines, fmt.Sprintf("topping %s %s %q", typ, code, t.Name))
+		}
+	}
+	sort.Strings(lines)
+	h := sha256.New()
+	for _, l := range lines {
+		fmt.Fprintln(h, l)
+	}
+	fmt.Fprintf(h, "%v\n%v\n", m.Categorization.Food.Categories, m.Categorization.Preconfigured.Categories)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func containsStr(list []string, s string) bool {
+	for _, elem := range list {
+		if elem == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package dawg
+
+import (
+	"bytes"
+	"encoding/gob"
+	"reflect"
+	"testing"
+)
+
+func TestMenuIndex(t *testing.T) {
+	m := testSearchMenu()
+	m.Categorization.Food = MenuCategory{
+		Categories: []MenuCategory{
+			{Name: "Pizza", Code: "Pizza", Categories: []MenuCategory{
+				{Name: "Build Your Own", Code: "BuildYourOwn", Products: []string{"S_PIZZA"}},
+				{Name: "Specialty Pizzas", Code: "Specialty", Products: []string{"S_PEPPER"}},
+			}},
+			{Name: "Bread", Code: "Bread", Products: []string{"S_BREAD"}},
+		},
+	}
+	m.Toppings = map[string]map[string]Topping{
+		"Pizza": {"P": {ItemCommon: ItemCommon{Code: "P", Name: "Pepperoni"}}},
+	}
+	idx := NewMenuIndex(m)
+
+	kinds := map[string]ItemKind{"S_PIZZA": ProductItem, "12SCREEN": VariantItem, "nothing": 0}
+	for code, kind := range kinds {
+		if k := idx.Kind(code); k != kind {
+			t.Errorf("wrong kind for %s: got %d, want %d", code, k, kind)
+		}
+	}
+	if v := idx.Variants("S_PIZZA"); !reflect.DeepEqual(v, []string{"10SCREEN", "12SCREEN"}) {
+		t.Errorf("wrong variants: %v", v)
+	}
+	if name := idx.ToppingName("Pizza", "P"); name != "Pepperoni" {
+		t.Errorf("wrong topping name %q", name)
+	}
+	if c := idx.Category("pizza/specialty"); !reflect.DeepEqual(c, []string{"S_PEPPER"}) {
+		t.Errorf("wrong category items: %v", c)
+	}
+	if c := idx.Category("Pizza"); !reflect.DeepEqual(c, []string{"S_PIZZA", "S_PEPPER"}) {
+		t.Errorf("parent categories should have all the items below them: %v", c)
+	}
+	if name := idx.CategoryName("S_PEPPER"); name != "Specialty Pizzas" {
+		t.Errorf("wrong category name %q", name)
+	}
+	if codes := idx.Lookup("Pepperoni"); !reflect.DeepEqual(codes, []string{"14SCPEPPER", "S_PEPPER"}) {
+		t.Errorf("wrong lookup: %v", codes)
+	}
+	if codes := idx.Lookup("vegetarian"); !reflect.DeepEqual(codes, []string{"S_BREAD"}) {
+		t.Errorf("tags should be indexed: %v", codes)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := gob.NewEncoder(buf).Encode(idx); err != nil {
+		t.Fatal(err)
+	}
+	decoded := new(MenuIndex)
+	if err := gob.NewDecoder(buf).Decode(decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(idx, decoded) {
+		t.Error("index should be the same after gob encoding")
+	}
+
+	m.SetIndex(decoded)
+	if m.Index() != decoded {
+		t.Error("menu should use the index it was given")
+	}
+	m.SetIndex(&MenuIndex{MenuID: "other"})
+	if m.Index() != decoded {
+		t.Error("menu should not use an index from another menu")
+	}
+	if _, ok := m.FindItem("12SCREEN").(*Variant); !ok {
+		t.Error("should find a variant through the index")
+	}
+	if m.FindItem("nothing") != nil {
+		t.Error("should not find an item that does not exist")
+	}
+
+	// a stale index should not be trusted over the menu
+	delete(m.Variants, "12SCREEN")
+	if m.FindItem("12SCREEN") != nil {
+		t.Error("should not find a variant that was removed after indexing")
+	}
+	m.Preconfigured = map[string]*PreConfiguredProduct{
+		"12SCREEN": {ItemCommon: ItemCommon{Code: "12SCREEN", Name: "Medium Hand Tossed"}},
+	}
+	if _, ok := m.FindItem("12SCREEN").(*PreConfiguredProduct); !ok {
+		t.Error("should fall back to searching the menu when the index is wrong")
+	}
+	for _, r := range m.Search("medium hand tossed", SearchOptions{}) {
+		if r.Item.ItemCode() == "12SCREEN" {
+			t.Error("should not find items that are only in a stale index")
+		}
+	}
+
+	// an index from an older menu of the same store should not be used
+	older := testSearchMenu()
+	oldIdx := NewMenuIndex(older)
+	newer := testSearchMenu()
+	delete(newer.Products, "S_PEPPER")
+	newer.SetIndex(oldIdx)
+	if newer.Index() == oldIdx {
+		t.Error("should not use an index from an older version of the menu")
+	}
+	older.SetIndex(oldIdx)
+	if older.Index() != oldIdx {
+		t.Error("should use an index from the same menu")
+	}
+}
@@ -35,7 +35,7 @@ type Item interface {
 type ItemCommon struct {
 	Code string
 	Name string
-	Tags map[string]interface{}
+	Tags ItemTags
 
 	// Local will tell you if the item was made locally
 	Local bool
@@ -43,6 +43,65 @@ type ItemCommon struct {
 	menu *Menu // not really sure how i feel about this... smells like OOP
 }
 
+// ItemTags are the tags that dominos gives each item on the menu. The values
+// can be of any json type, so use the accessor methods instead of type
+// assertions.
+type ItemTags map[string]interface{}
+
+// String returns a tag's value if it is a string and "" if it is not.
+func (t ItemTags) String(key string) string {
+	s, _ := t[key].(string)
+	return s
+}
+
+// Bool returns true if a tag is set to true.
+func (t ItemTags) Bool(key string) bool {
+	b, _ := t[key].(bool)
+	return b
+}
+
+// Strings returns a tag's value if it is a list of strings. Values in the
+// list that are not strings are left out, and a single string is treated
+// like a list of one.
+func (t ItemTags) Strings(key string) []string {
+	switch val := t[key].(type) {
+	case []string:
+		return val
+	case string:
+		return []string{val}
+	case []interface{}:
+		list := make([]string, 0, len(val))
+		for _, v := range val {
+			if s, ok := v.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
+// OptionQtys are the amounts that a topping can be added in.
+func (t ItemTags) OptionQtys() []string {
+	return t.Strings("OptionQtys")
+}
+
+// DefaultToppings are the toppings that an item comes with as a comma
+// separated list of key-value pairs.
+func (t ItemTags) DefaultToppings() string {
+	return t.String("DefaultToppings")
+}
+
+// Size is the size code of an item.
+func (t ItemTags) Size() string {
+	return t.String("Size")
+}
+
+// Flavor is the flavor code of an item.
+func (t ItemTags) Flavor() string {
+	return t.String("Flavor")
+}
+
 // ItemCode is a getter method for the Code field.
 func (im *ItemCommon) ItemCode() string {
 	return im.Code
@@ -141,16 +200,74 @@ func (p *Product) GetVariants(container ItemContainer) (variants []*Variant) {
 	return variants
 }
 
-func (p *Product) optionQtys() (optqtys []string) {
-	if qtys, ok := p.Tags["OptionQtys"]; ok {
-		oq := qtys.([]interface{})
-		optqtys = make([]string, len(oq))
-		for i, q := range oq {
-			optqtys[i] = q.(string)
+func (p *Product) optionQtys() []string {
+	return p.Tags.OptionQtys()
+}
+
+// VariantFor finds the variant of the product that comes in a size and
+// flavor, like VariantFor("large", "thin crust"). The size and flavor can be
+// codes or names, and an empty size or flavor matches any of them. The
+// product has to have come from the menu (see Menu.GetProduct and
+// Menu.FindItem).
+func (p *Product) VariantFor(size, flavor string) (*Variant, error) {
+	if p.menu == nil {
+		return nil, fmt.Errorf("%s was not found through a menu", p.Code)
+	}
+	var found []*Variant
+	for _, v := range p.GetVariants(p.menu) {
+		if size != "" && !matchesName(size, v.sizeCode(), p.sizeName(v)) {
+			continue
+		}
+		if flavor != "" && !matchesName(flavor, v.flavorCode(), p.flavorName(v)) {
+			continue
 		}
-		return optqtys
+		found = append(found, v)
 	}
-	return nil
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("%s does not come in %s", p.Name, strings.TrimSpace(size+" "+flavor))
+	case 1:
+		return found[0], nil
+	}
+	codes := make([]string, len(found))
+	for i, v := range found {
+		codes[i] = v.Code
+	}
+	return nil, fmt.Errorf("more than one %s matches %s: %s",
+		p.Name, strings.TrimSpace(size+" "+flavor), strings.Join(codes, ", "))
+}
+
+func (p *Product) sizeName(v *Variant) string {
+	if s, ok := p.menu.Size(p.ProductType, v.sizeCode()); ok {
+		return s.Name
+	}
+	return ""
+}
+
+func (p *Product) flavorName(v *Variant) string {
+	if f, ok := p.menu.Flavor(p.ProductType, v.flavorCode()); ok {
+		return f.Name
+	}
+	return ""
+}
+
+// matchesName tells whether a query is a code or all the words in it are
+// in a name.
+func matchesName(query, code, name string) bool {
+	if code != "" && strings.EqualFold(strings.TrimSpace(query), code) {
+		return true
+	}
+	words := searchTokens(query)
+	if len(words) == 0 || name == "" {
+		return false
+	}
+	nameWords := searchTokens(name)
+	for _, w := range words {
+		if !containsStr(nameWords, w) {
+			return false
+		}
+	}
+	return true
 }
 
 // Variant is a structure that represents a base component of the Dominos menu.
@@ -159,7 +276,15 @@ type Variant struct {
 	ItemCommon
 
 	// the price of the variant.
-	Price string
+	Price Money
+
+	// SizeCode is the code for the size of the variant, like "12" for a
+	// medium pizza.
+	SizeCode string
+
+	// FlavorCode is the code for the flavor of the variant, like "HANDTOSS"
+	// for a hand tossed pizza.
+	FlavorCode string
 
 	// Product Code is the code for the set of variants that the variant belongs
 	// to. Will coorespond with the code field of one Product.
@@ -174,8 +299,8 @@ type Variant struct {
 
 // Options returns a map of the Variant's options.
 func (v *Variant) Options() map[string]interface{} {
-	if options, ok := v.Tags["DefaultToppings"]; ok {
-		codes, amounts, n := splitDefaults(options.(string))
+	if options := v.Tags.DefaultToppings(); options != "" {
+		codes, amounts, n := splitDefaults(options)
 
 		if v.opts == nil {
 			v.opts = make(map[string]interface{})
@@ -217,6 +342,47 @@ func (v *Variant) Category() string {
 	return v.GetProduct().Category()
 }
 
+// Size finds the size of the variant on the menu that it came from. Returns
+// false if the variant was not found through a menu or the menu does not
+// have its size.
+func (v *Variant) Size() (Size, bool) {
+	if v.menu == nil {
+		return Size{}, false
+	}
+	return v.menu.Size(v.productType(), v.sizeCode())
+}
+
+// Flavor finds the flavor of the variant on the menu that it came from.
+// Returns false if the variant was not found through a menu or the menu does
+// not have its flavor.
+func (v *Variant) Flavor() (Flavor, bool) {
+	if v.menu == nil {
+		return Flavor{}, false
+	}
+	return v.menu.Flavor(v.productType(), v.flavorCode())
+}
+
+func (v *Variant) sizeCode() string {
+	if v.SizeCode != "" {
+		return v.SizeCode
+	}
+	return v.Tags.Size()
+}
+
+func (v *Variant) flavorCode() string {
+	if v.FlavorCode != "" {
+		return v.FlavorCode
+	}
+	return v.Tags.Flavor()
+}
+
+func (v *Variant) productType() string {
+	if p := v.GetProduct(); p != nil {
+		return p.ProductType
+	}
+	return ""
+}
+
 // GetProduct will return the set of variants (Product) that the variant
 // is a member of.
 func (v *Variant) GetProduct() *Product {
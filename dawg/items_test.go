@@ -1,6 +1,7 @@
 package dawg
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/harrybrwn/apizza/pkg/tests"
@@ -123,3 +124,101 @@ func TestViewOptions(t *testing.T) {
 		}
 	}
 }
+
+func TestItemTags(t *testing.T) {
+	var tags ItemTags
+	tests.InitHelpers(t)
+	tests.Check(json.Unmarshal([]byte(`{
+		"OptionQtys": ["0", 1, "1.5"],
+		"DefaultToppings": "X=1,C=1",
+		"Size": 14,
+		"Flavor": "HANDTOSS",
+		"Vegetarian": true,
+		"Specialty": "yes"
+	}`), &tags))
+	if q := tags.OptionQtys(); len(q) != 2 || q[0] != "0" || q[1] != "1.5" {
+		t.Errorf("wrong option quantities: %v", q)
+	}
+	tests.StrEq(tags.DefaultToppings(), "X=1,C=1", "wrong default toppings")
+	tests.StrEq(tags.Size(), "", "a size that is not a string should be empty")
+	tests.StrEq(tags.Flavor(), "HANDTOSS", "wrong flavor")
+	if !tags.Bool("Vegetarian") || tags.Bool("Specialty") || tags.Bool("Missing") {
+		t.Error("wrong bool tags")
+	}
+	if tags.Strings("Missing") != nil {
+		t.Error("missing tags should be nil")
+	}
+
+	// bad tags should not panic
+	p := &Product{ItemCommon: ItemCommon{Tags: ItemTags{"OptionQtys": "1"}}}
+	tests.Check(p.AddTopping("X", ToppingFull, "1"))
+	v := &Variant{ItemCommon: ItemCommon{Tags: ItemTags{"DefaultToppings": 5}}}
+	if len(v.Options()) != 0 {
+		t.Error("a bad DefaultToppings tag should give no options")
+	}
+}
+
+func TestVariantFor(t *testing.T) {
+	tests.InitHelpers(t)
+	m := &Menu{
+		Products: map[string]*Product{
+			"S_PIZZA": {
+				ItemCommon:  ItemCommon{Code: "S_PIZZA", Name: "Pizza"},
+				ProductType: "Pizza",
+				Variants:    []string{"12SCREEN", "14SCREEN", "14THIN"},
+			},
+		},
+		Variants: map[string]*Variant{
+			"12SCREEN": {ItemCommon: ItemCommon{Code: "12SCREEN"}, ProductCode: "S_PIZZA", SizeCode: "12", FlavorCode: "HANDTOSS"},
+			"14SCREEN": {ItemCommon: ItemCommon{Code: "14SCREEN"}, ProductCode: "S_PIZZA", SizeCode: "14", FlavorCode: "HANDTOSS"},
+			"14THIN": {
+				ItemCommon:  ItemCommon{Code: "14THIN", Tags: ItemTags{"Size": "14", "Flavor": "THIN"}},
+				ProductCode: "S_PIZZA",
+			},
+		},
+		Sizes: map[string]map[string]Size{"Pizza": {
+			"12": {Code: "12", Name: `Medium (12")`},
+			"14": {Code: "14", Name: `Large (14")`},
+		}},
+		Flavors: map[string]map[string]Flavor{"Pizza": {
+			"HANDTOSS": {Code: "HANDTOSS", Name: "Hand Tossed"},
+			"THIN":     {Code: "THIN", Name: "Crunchy Thin Crust"},
+		}},
+	}
+
+	if _, err := m.Products["S_PIZZA"].VariantFor("large", ""); err == nil {
+		t.Error("a product that is not from the menu should give an error")
+	}
+	p, err := m.GetProduct("S_PIZZA")
+	tests.Check(err)
+	for _, tc := range []struct{ size, flavor, exp string }{
+		{"large", "thin crust", "14THIN"},
+		{"Large", "hand tossed", "14SCREEN"},
+		{"12", "", "12SCREEN"},
+		{"medium", "HANDTOSS", "12SCREEN"},
+		{"", "thin", "14THIN"},
+	} {
+		v, err := p.VariantFor(tc.size, tc.flavor)
+		if err != nil {
+			t.Errorf("%s %s: %v", tc.size, tc.flavor, err)
+			continue
+		}
+		tests.StrEq(v.Code, tc.exp, "wrong variant for %s %s", tc.size, tc.flavor)
+	}
+	_, err = p.VariantFor("large", "")
+	tests.Exp(err, "more than one variant should match")
+	_, err = p.VariantFor("small", "")
+	tests.Exp(err, "no variants should match")
+
+	v, err := m.GetVariant("14THIN")
+	tests.Check(err)
+	if s, ok := v.Size(); !ok || s.Name != `Large (14")` {
+		t.Errorf("wrong size: %+v", s)
+	}
+	if f, ok := v.Flavor(); !ok || f.Name != "Crunchy Thin Crust" {
+		t.Errorf("wrong flavor: %+v", f)
+	}
+	if _, ok := (&Variant{}).Size(); ok {
+		t.Error("a variant without a menu should not have a size")
+	}
+}
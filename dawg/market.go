@@ -0,0 +1,232 @@
+package dawg
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Market is a country that dominos sells pizza in. Each market has its own
+// dominos host, language, currency, and address format.
+//
+// The package level functions like NearestStore and SignIn use the
+// UnitedStates market. Use a market's methods to work with stores in other
+// countries.
+type Market struct {
+	// Code is the two letter country code of the market, like "US" or "CA".
+	Code string
+	// Name is the name of the country.
+	Name string
+	// Host is the dominos host that serves the market.
+	Host string
+	// Lang is the language code sent to dominos.
+	Lang string
+	// Currency is the ISO 4217 code of the currency that prices are in.
+	Currency string
+
+	// RegionName is what the market calls the region of an address, like
+	// "state" or "province".
+	RegionName string
+	// PostalName is what the market calls a postal code, like "zip code".
+	PostalName string
+	// Regions are the region codes that can be used in an address.
+	Regions []string
+
+	trackerName  string // the market's name for the order tracker
+	postal       *regexp.Regexp
+	formatPostal func(code string) string
+	cli          *client // nil for the UnitedStates, see Market.client
+}
+
+var (
+	// UnitedStates is the market for dominos in the United States.
+	UnitedStates = &Market{
+		Code:       "US",
+		Name:       "United States",
+		Host:       orderHost,
+		Lang:       DefaultLang,
+		Currency:   "USD",
+		RegionName: "state",
+		PostalName: "zip code",
+		Regions: []string{
+			"AL", "AK", "AZ", "AR", "CA", "CO", "CT", "DE", "DC", "FL", "GA",
+			"HI", "ID", "IL", "IN", "IA", "KS", "KY", "LA", "ME", "MD", "MA",
+			"MI", "MN", "MS", "MO", "MT", "NE", "NV", "NH", "NJ", "NM", "NY",
+			"NC", "ND", "OH", "OK", "OR", "PA", "RI", "SC", "SD", "TN", "TX",
+			"UT", "VT", "VA", "WA", "WV", "WI", "WY", "PR", "GU", "VI",
+		},
+		trackerName: "UNITED_STATES",
+		postal:      regexp.MustCompile(`^[0-9]{5}(-?[0-9]{4})?$`),
+		formatPostal: func(code string) string {
+			// zip+4 codes are written with a dash
+			code = strings.Replace(code, "-", "", 1)
+			if len(code) == 9 {
+				return code[:5] + "-" + code[5:]
+			}
+			return code
+		},
+	}
+
+	// Canada is the market for dominos in Canada.
+	Canada = &Market{
+		Code:       "CA",
+		Name:       "Canada",
+		Host:       "order.dominos.ca",
+		Lang:       "en",
+		Currency:   "CAD",
+		RegionName: "province",
+		PostalName: "postal code",
+		Regions: []string{
+			"AB", "BC", "MB", "NB", "NL", "NS", "NT", "NU", "ON", "PE", "QC",
+			"SK", "YT",
+		},
+		trackerName: "CANADA",
+		postal:      regexp.MustCompile(`^[ABCEGHJ-NPRSTVXY][0-9][ABCEGHJ-NPRSTV-Z] ?[0-9][ABCEGHJ-NPRSTV-Z][0-9]$`),
+		formatPostal: func(code string) string {
+			code = strings.Replace(code, " ", "", 1)
+			return code[:3] + " " + code[3:]
+		},
+	}
+
+	// Markets is a list of all the markets.
+	Markets = []*Market{UnitedStates, Canada}
+)
+
+var marketAliases = map[string]*Market{
+	"us":            UnitedStates,
+	"usa":           UnitedStates,
+	"united states": UnitedStates,
+	"ca":            Canada,
+	"can":           Canada,
+	"canada":        Canada,
+}
+
+func init() {
+	// the client points back to its market, so it is set up here to avoid an
+	// initialization loop.
+	Canada.cli = &client{host: Canada.Host, market: Canada, Client: orderClient.Client}
+}
+
+// GetMarket finds a market by its country code or name. Names are not case
+// sensitive.
+func GetMarket(name string) (*Market, error) {
+	if m, ok := marketAliases[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return m, nil
+	}
+	codes := make([]string, len(Markets))
+	for i, m := range Markets {
+		codes[i] = m.Code
+	}
+	return nil, fmt.Errorf("unknown market %q, use one of %s", name, strings.Join(codes, ", "))
+}
+
+func (m *Market) String() string {
+	return m.Code
+}
+
+// NearestStore gets the store in the market that is closest to the given
+// address.
+func (m *Market) NearestStore(addr Address, service ServiceMethod) (*Store, error) {
+	return getNearestStore(m.client(), addr, service)
+}
+
+// GetNearbyStores gets all of the stores in the market that are near the
+// address.
+func (m *Market) GetNearbyStores(addr Address, service ServiceMethod) ([]*Store, error) {
+	return asyncNearbyStores(m.client(), addr, service)
+}
+
+// NewStore gets a store in the market by its id. See NewStore.
+func (m *Market) NewStore(id string, service ServiceMethod, addr Address) (*Store, error) {
+	store := &Store{userService: service, userAddress: addr}
+	return store, initStore(m.client(), id, store)
+}
+
+// DecodeStore makes a store in the market from the json store data that
+// dominos sent earlier, without connecting to dominos. The service and
+// address are used for the orders made by the store, the same as NewStore.
+func (m *Market) DecodeStore(data []byte, service ServiceMethod, addr Address) (*Store, error) {
+	store := &Store{userService: service, userAddress: addr, cli: m.client()}
+	return store, json.Unmarshal(data, store)
+}
+
+// SignIn will create a new UserProfile and sign in to the market's dominos
+// account.
+func (m *Market) SignIn(username, password string) (*UserProfile, error) {
+	a, err := newauth(m, username, password)
+	if err != nil {
+		return nil, err
+	}
+	return a.login()
+}
+
+// PostalCode checks that a postal code is valid in the market and returns it
+// in the market's format, like "M5V 3L9" for "m5v3l9".
+func (m *Market) PostalCode(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !m.postal.MatchString(code) {
+		return "", fmt.Errorf("%q is not a valid %s %s", code, m.Name, m.PostalName)
+	}
+	return m.formatPostal(code), nil
+}
+
+// ValidRegion tells whether a region code, like a state or province, is in
+// the market.
+func (m *Market) ValidRegion(code string) bool {
+	code = strings.ToUpper(code)
+	for _, r := range m.Regions {
+		if r == code {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateAddress returns an error if the address does not have a region and
+// postal code from the market.
+func (m *Market) ValidateAddress(addr Address) error {
+	if !m.ValidRegion(addr.StateCode()) {
+		return fmt.Errorf("%q is not a %s in %s", addr.StateCode(), m.RegionName, m.Name)
+	}
+	_, err := m.PostalCode(addr.Zip())
+	return err
+}
+
+// client returns the client for the market's host. The UnitedStates uses the
+// package's default client.
+func (m *Market) client() *client {
+	if m == UnitedStates || m.cli == nil {
+		return orderClient
+	}
+	return m.cli
+}
+
+// getMarket returns the client's market, which is the UnitedStates if the
+// client does not have one.
+func (c *client) getMarket() *Market {
+	if c == nil || c.market == nil {
+		return UnitedStates
+	}
+	return c.market
+}
+
+// Market returns the market that the store is in.
+func (s *Store) Market() *Market {
+	return s.cli.getMarket()
+}
+
+// Market returns the market that the order will be sent to.
+func (o *Order) Market() *Market {
+	return o.cli.getMarket()
+}
+
+// SetMarket sets the market that the order is sent to. Orders that are
+// decoded from json do not have a market and are sent to the UnitedStates
+// unless they are given one.
+func (o *Order) SetMarket(m *Market) {
+	if o.cli.getMarket() != m || o.cli == nil {
+		o.cli = m.client()
+	}
+	o.LanguageCode = m.Lang
+}
@@ -0,0 +1,143 @@
+package dawg
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestGetMarket(t *testing.T) {
+	tests.InitHelpers(t)
+	for name, exp := range map[string]*Market{
+		"US": UnitedStates, "usa": UnitedStates, "ca": Canada, " Canada ": Canada,
+	} {
+		m, err := GetMarket(name)
+		tests.Check(err)
+		if m != exp {
+			t.Errorf("%q: got market %s, want %s", name, m, exp)
+		}
+	}
+	_, err := GetMarket("mars")
+	tests.Exp(err, "expected an error for an unknown market")
+}
+
+func TestMarket_PostalCode(t *testing.T) {
+	tests.InitHelpers(t)
+	code, err := Canada.PostalCode("m5v3l9")
+	tests.Check(err)
+	tests.StrEq(code, "M5V 3L9", "canadian postal codes should be formatted")
+	code, err = Canada.PostalCode("K1A 0B1")
+	tests.Check(err)
+	tests.StrEq(code, "K1A 0B1", "wrong postal code")
+	_, err = Canada.PostalCode("20500")
+	tests.Exp(err, "zip codes are not canadian postal codes")
+	_, err = Canada.PostalCode("D1A 0B1")
+	tests.Exp(err, "postal codes cannot start with D")
+
+	code, err = UnitedStates.PostalCode(" 20500")
+	tests.Check(err)
+	tests.StrEq(code, "20500", "wrong zip code")
+	code, err = UnitedStates.PostalCode("627041234")
+	tests.Check(err)
+	tests.StrEq(code, "62704-1234", "zip+4 codes should have a dash")
+	_, err = UnitedStates.PostalCode("M5V 3L9")
+	tests.Exp(err, "postal codes are not zip codes")
+}
+
+func TestMarket_DecodeStore(t *testing.T) {
+	tests.InitHelpers(t)
+	toronto := &StreetAddr{Street: "100 Queen St W", CityName: "Toronto", State: "ON", Zipcode: "M5H 2N2"}
+	store, err := Canada.DecodeStore([]byte(`{"StoreID":"10001","Phone":"416-555-0100"}`), Carryout, toronto)
+	tests.Check(err)
+	tests.StrEq(store.ID, "10001", "wrong store id")
+	if store.Market() != Canada {
+		t.Error("store should be in the canadian market")
+	}
+	o := store.NewOrder()
+	tests.StrEq(o.StoreID, "10001", "wrong store id on the order")
+	tests.StrEq(o.Address.City(), "Toronto", "order should use the store's address")
+	tests.StrEq(string(o.ServiceMethod), string(Carryout), "wrong service")
+	tests.StrEq(o.LanguageCode, Canada.Lang, "wrong language")
+
+	_, err = UnitedStates.DecodeStore([]byte(`{`), Delivery, toronto)
+	tests.Exp(err, "should not decode bad json")
+}
+
+func TestMarket_ValidateAddress(t *testing.T) {
+	tests.InitHelpers(t)
+	toronto := &StreetAddr{Street: "100 Queen St W", CityName: "Toronto", State: "ON", Zipcode: "M5H 2N2"}
+	tests.Check(Canada.ValidateAddress(toronto))
+	tests.Exp(UnitedStates.ValidateAddress(toronto), "ontario is not a state")
+	tests.Check(UnitedStates.ValidateAddress(testAddress()))
+	tests.Exp(Canada.ValidateAddress(testAddress()), "DC is not a province")
+}
+
+func TestMarket_Client(t *testing.T) {
+	tests.InitHelpers(t)
+	var host, lang string
+	c := &client{host: Canada.Host, market: Canada, Client: &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			host, lang = r.URL.Host, r.URL.Query().Get("lang")
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{"Status":0}`)),
+			}, nil
+		}),
+	}}
+	_, err := newMenu(c, "10001")
+	tests.Check(err)
+	tests.StrEq(host, "order.dominos.ca", "canadian menus should come from the canadian host")
+	tests.StrEq(lang, Canada.Lang, "wrong menu language")
+
+	store := &Store{ID: "10001", cli: c, userAddress: testAddress()}
+	if store.Market() != Canada {
+		t.Error("store should be in the canadian market")
+	}
+	o := store.NewOrder()
+	if o.Market() != Canada {
+		t.Error("orders should be in the same market as their store")
+	}
+
+	o = &Order{}
+	if o.Market() != UnitedStates {
+		t.Error("orders without a client should be sent to the united states")
+	}
+	o.SetMarket(Canada)
+	if o.Market() != Canada || o.cli.host != Canada.Host {
+		t.Error("order market was not set")
+	}
+	o.SetMarket(UnitedStates)
+	if o.cli != orderClient {
+		t.Error("united states orders should use the default client")
+	}
+}
+
+func TestMarket_Currency(t *testing.T) {
+	tests.InitHelpers(t)
+	c := &client{host: Canada.Host, market: Canada, Client: &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: ioutil.NopCloser(bytes.NewBufferString(`{"Status":0,
+					"Variants":{"14SCREEN":{"Code":"14SCREEN","Price":"13.99"}},
+					"Order":{"Status":0,"OrderID":"abc","Amounts":{"Customer":20.5}}}`)),
+			}, nil
+		}),
+	}}
+	m, err := newMenu(c, "10001")
+	tests.Check(err)
+	tests.StrEq(m.Currency, "CAD", "the menu should be in the market's currency")
+	tests.StrEq(m.Variants["14SCREEN"].Price.String(), "CA$13.99", "prices should be in the market's currency")
+
+	o := &Order{cli: c}
+	tests.Exp(o.SetTip(Cents(100)), "the tip should be in the order's currency")
+	tests.Check(o.SetTip(Cents(100).In("CAD")))
+	price, err := o.Price()
+	tests.Check(err)
+	if price != Cents(2050).In("CAD") || o.PricedAmounts()["Customer"].Currency != "CAD" {
+		t.Errorf("the order should be priced in canadian dollars; got %v", price)
+	}
+}
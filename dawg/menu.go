@@ -46,7 +46,17 @@ type Menu struct {
 		Description string
 	}
 
-	cli *client
+	// Sizes and Flavors are the sizes and flavors (crusts for pizza) that
+	// each product type comes in, keyed by product type and then code.
+	Sizes   map[string]map[string]Size
+	Flavors map[string]map[string]Flavor
+
+	// Currency is the currency of the menu's prices, which comes from the
+	// store's market.
+	Currency string `json:"-"`
+
+	cli   *client
+	index *MenuIndex
 }
 
 // MenuCategory is a category on the dominos menu.
@@ -73,7 +83,7 @@ func (m MenuCategory) IsEmpty() bool {
 func (m *Menu) GetProduct(code string) (prod *Product, err error) {
 	var ok bool
 	if prod, ok = m.Products[code]; ok {
-		return prod, nil
+		return m.initProduct(prod), nil
 	}
 	return nil, fmt.Errorf("could not find product '%s'", code)
 }
@@ -89,11 +99,29 @@ func (m *Menu) GetVariant(code string) (*Variant, error) {
 // FindItem looks in all the different menu categories for an item code given
 // as an argument.
 func (m *Menu) FindItem(code string) (itm Item) {
+	if m.index != nil {
+		// the index can be out of date if the maps were changed after it
+		// was built, so fall through to the maps if it is wrong
+		switch m.index.Kind(code) {
+		case ProductItem:
+			if p, ok := m.Products[code]; ok {
+				return m.initProduct(p)
+			}
+		case PreconfiguredItem:
+			if p, ok := m.Preconfigured[code]; ok {
+				return p
+			}
+		case VariantItem:
+			if v, ok := m.Variants[code]; ok {
+				return m.initVariant(v)
+			}
+		}
+	}
 	var ok bool
 	var i interface{}
 
 	if i, ok = m.Products[code]; ok {
-		return i.(*Product)
+		return m.initProduct(i.(*Product))
 	} else if i, ok = m.Preconfigured[code]; ok {
 		return i.(*PreConfiguredProduct)
 	} else if i, ok = m.Variants[code]; ok {
@@ -102,6 +130,26 @@ func (m *Menu) FindItem(code string) (itm Item) {
 	return nil
 }
 
+// EstimatePrice adds up the menu prices of the products in an order. It does
+// not need to connect to dominos, but it leaves out taxes, delivery fees,
+// coupons, and the price of extra toppings, so use Order.Price for the real
+// price.
+func (m *Menu) EstimatePrice(o *Order) (Money, error) {
+	var total Money
+	for _, p := range o.Products {
+		v, ok := m.Variants[p.Code]
+		if !ok {
+			return Money{}, fmt.Errorf("could not find the price of '%s'", p.Code)
+		}
+		qty := p.Qty
+		if qty < 1 {
+			qty = 1
+		}
+		total = total.Add(v.Price.Mul(qty))
+	}
+	return total, nil
+}
+
 // Print will write the menu to an io.Writer.
 func (m *Menu) Print(w io.Writer) {
 	writeMenuCategory(w, m.Categorization.Food, 0)
@@ -132,6 +180,38 @@ func (m *Menu) ViewOptions(itm Item) map[string]string {
 	return ReadableToppings(itm, m)
 }
 
+// Size is one of the sizes that a type of product comes in, like
+// "Large (14\")" for pizza.
+type Size struct {
+	Code        string
+	Name        string
+	Description string
+	Local       bool
+	SortSeq     string
+}
+
+// Flavor is one of the flavors that a type of product comes in. For pizza
+// the flavors are the crusts, like "Hand Tossed" or "Crunchy Thin Crust".
+type Flavor struct {
+	Code        string
+	Name        string
+	Description string
+	Local       bool
+	SortSeq     string
+}
+
+// Size finds the size of a product type on the menu.
+func (m *Menu) Size(productType, code string) (Size, bool) {
+	s, ok := m.Sizes[productType][code]
+	return s, ok
+}
+
+// Flavor finds the flavor of a product type on the menu.
+func (m *Menu) Flavor(productType, code string) (Flavor, bool) {
+	f, ok := m.Flavors[productType][code]
+	return f, ok
+}
+
 // Topping is a simple struct that represents a topping on the menu.
 //
 // Note: this struct does not rempresent a topping that is added to an Item
@@ -233,17 +313,30 @@ func validateQtys(amount string, qtys []string) bool {
 
 func (m *Menu) initVariant(v *Variant) *Variant {
 	if parent, ok := m.Products[v.ProductCode]; ok {
-		v.product = parent
+		v.product = m.initProduct(parent)
 	}
+	v.menu = m
 	return v
 }
 
+func (m *Menu) initProduct(p *Product) *Product {
+	p.menu = m
+	return p
+}
+
 func newMenu(c *client, id string) (*Menu, error) {
 	path := format("/power/store/%s/menu", id)
-	b, err := c.get(path, Params{"lang": DefaultLang, "structured": "true"})
+	b, err := c.get(path, Params{"lang": c.getMarket().Lang, "structured": "true"})
 	if err != nil {
 		return nil, err
 	}
-	menu := &Menu{ID: id, cli: c}
-	return menu, errpair(json.Unmarshal(b, menu), dominosErr(b))
+	menu := &Menu{ID: id, Currency: c.getMarket().Currency, cli: c}
+	if err = errpair(json.Unmarshal(b, menu), dominosErr(b)); err != nil {
+		return menu, err
+	}
+	for _, v := range menu.Variants {
+		v.Price = v.Price.In(menu.Currency)
+	}
+	menu.index = NewMenuIndex(menu)
+	return menu, nil
 }
@@ -210,3 +210,22 @@ func TestMenuStorage(t *testing.T) {
 	}
 	tests.Check(os.RemoveAll(testdir))
 }
+
+func TestEstimatePrice(t *testing.T) {
+	m := testSearchMenu()
+	o := &Order{Products: []*OrderProduct{
+		{ItemCommon: ItemCommon{Code: "12SCREEN"}, Qty: 2},
+		{ItemCommon: ItemCommon{Code: "B8PCSCB"}, Qty: 1},
+	}}
+	price, err := m.EstimatePrice(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price != Cents(2697) {
+		t.Errorf("wrong estimate: got %v, want 26.97", price)
+	}
+	o.Products = append(o.Products, &OrderProduct{ItemCommon: ItemCommon{Code: "NOPE"}, Qty: 1})
+	if _, err = m.EstimatePrice(o); err == nil {
+		t.Error("expected an error for an item that is not on the menu")
+	}
+}
@@ -0,0 +1,275 @@
+package dawg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency given to money that is parsed without one.
+// Dominos sends prices as plain numbers in the currency of the store, so
+// prices from a menu or an order are given the currency of their Market.
+const DefaultCurrency = "USD"
+
+// currencySymbols are the symbols used when formatting money. Currencies that
+// are not here are formatted with their code after the amount.
+var currencySymbols = map[string]string{
+	"":    "$",
+	"USD": "$",
+	"CAD": "CA$",
+}
+
+// Money is an amount of money stored as a whole number of the currency's
+// minor units (cents) so that adding up prices and splitting payments does
+// not pick up floating point rounding errors.
+//
+// The zero value is zero money with no currency and can be combined with
+// money in any currency.
+type Money struct {
+	// Units is the amount in minor units, so 799 is $7.99.
+	Units int64
+
+	// Currency is the ISO 4217 code of the currency.
+	Currency string
+}
+
+// Cents creates money in the default currency from a number of cents.
+func Cents(n int64) Money {
+	return Money{Units: n, Currency: DefaultCurrency}
+}
+
+// MoneyFromFloat creates money in the default currency from a number of
+// dollars, rounding to the nearest cent.
+func MoneyFromFloat(f float64) Money {
+	return Cents(int64(math.Round(f * 100)))
+}
+
+// ParseMoney parses an amount like "7.99", "$1,299.00", or "-0.5" into money
+// in the default currency. Amounts with more than two decimal places are
+// rounded to the nearest cent.
+func ParseMoney(s string) (Money, error) {
+	str := strings.TrimSpace(s)
+	neg := strings.HasPrefix(str, "-")
+	if neg {
+		str = str[1:]
+	}
+	str = strings.Replace(strings.TrimPrefix(str, "$"), ",", "", -1)
+	units, ok := parseCents(str)
+	if !ok {
+		return Money{}, fmt.Errorf("cannot parse %q as money", s)
+	}
+	if neg {
+		units = -units
+	}
+	return Cents(units), nil
+}
+
+// parseCents parses a positive number of dollars into cents without going
+// through a float, so "0.285" is rounded up to 29 cents.
+func parseCents(s string) (int64, bool) {
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+	if (whole == "" && frac == "") || !isDigits(whole) || !isDigits(frac) {
+		return 0, false
+	}
+	roundUp := len(frac) > 2 && frac[2] >= '5'
+	cents := (frac + "00")[:2]
+	units, err := strconv.ParseInt(whole+cents, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	if roundUp {
+		units++
+	}
+	return units, true
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Float returns the amount in dollars. It should only be used for display.
+func (m Money) Float() float64 {
+	return float64(m.Units) / 100
+}
+
+// IsZero returns true if there is no money.
+func (m Money) IsZero() bool {
+	return m.Units == 0
+}
+
+// Decimal formats the amount without a currency symbol, like "7.99".
+func (m Money) Decimal() string {
+	units, sign := m.Units, ""
+	if units < 0 {
+		units, sign = -units, "-"
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, units/100, units%100)
+}
+
+// String formats the amount with the currency's symbol, like "$7.99",
+// "-$0.50", or "CA$7.99".
+func (m Money) String() string {
+	s := m.Decimal()
+	sym, ok := currencySymbols[m.Currency]
+	if !ok {
+		return s + " " + m.Currency
+	}
+	if strings.HasPrefix(s, "-") {
+		return "-" + sym + s[1:]
+	}
+	return sym + s
+}
+
+// In returns the same amount in another currency. It does not convert
+// between currencies, it is for giving money that was parsed without a
+// currency the right one.
+func (m Money) In(currency string) Money {
+	m.Currency = currency
+	return m
+}
+
+// Add returns the sum of two amounts. It panics if they are in different
+// currencies.
+func (m Money) Add(o Money) Money {
+	return Money{Units: m.Units + o.Units, Currency: m.currency(o)}
+}
+
+// Sub returns the difference of two amounts. It panics if they are in
+// different currencies.
+func (m Money) Sub(o Money) Money {
+	return Money{Units: m.Units - o.Units, Currency: m.currency(o)}
+}
+
+// Mul multiplies the amount by a quantity.
+func (m Money) Mul(n int) Money {
+	return Money{Units: m.Units * int64(n), Currency: m.Currency}
+}
+
+// Cmp compares two amounts and returns -1, 0, or 1 if m is less than, equal
+// to, or greater than o. It panics if they are in different currencies.
+func (m Money) Cmp(o Money) int {
+	m.currency(o)
+	switch {
+	case m.Units < o.Units:
+		return -1
+	case m.Units > o.Units:
+		return 1
+	}
+	return 0
+}
+
+// Split divides the amount into n parts that add up to exactly the original
+// amount. The leftover cents go to the first parts.
+func (m Money) Split(n int) []Money {
+	if n <= 0 {
+		return nil
+	}
+	return m.Allocate(repeatInt(1, n))
+}
+
+// Allocate divides the amount into parts proportional to the weights given.
+// The parts always add up to the original amount, with the leftover cents
+// going to the first parts. All of the parts are zero if the weights add up
+// to zero.
+func (m Money) Allocate(weights []int) []Money {
+	parts := make([]Money, len(weights))
+	var total int64
+	for _, w := range weights {
+		total += int64(w)
+	}
+	if total == 0 {
+		for i := range parts {
+			parts[i].Currency = m.Currency
+		}
+		return parts
+	}
+	left := m.Units
+	for i, w := range weights {
+		parts[i] = Money{Units: m.Units * int64(w) / total, Currency: m.Currency}
+		left -= parts[i].Units
+	}
+	step := int64(1)
+	if left < 0 {
+		step = -1
+	}
+	for i := 0; left != 0; i = (i + 1) % len(parts) {
+		if weights[i] == 0 {
+			continue
+		}
+		parts[i].Units += step
+		left -= step
+	}
+	return parts
+}
+
+func (m Money) currency(o Money) string {
+	switch {
+	case m.Currency == "":
+		return o.Currency
+	case o.Currency == "" || o.Currency == m.Currency:
+		return m.Currency
+	}
+	panic(fmt.Sprintf("dawg: cannot combine %s and %s", m.Currency, o.Currency))
+}
+
+// MarshalJSON writes the amount as a json number, which is what dominos
+// expects.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.Decimal()), nil
+}
+
+// UnmarshalJSON reads money from either a json number or a string. Empty
+// strings and null are zero.
+func (m *Money) UnmarshalJSON(b []byte) (err error) {
+	b = bytes.TrimSpace(b)
+	if bytes.Equal(b, []byte("null")) {
+		*m = Money{}
+		return nil
+	}
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		if err = json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		if strings.TrimSpace(s) == "" {
+			*m = Money{}
+			return nil
+		}
+		*m, err = ParseMoney(s)
+		return err
+	}
+	var n json.Number
+	if err = json.Unmarshal(b, &n); err != nil {
+		return errors.New("money must be a number or a string")
+	}
+	if *m, err = ParseMoney(n.String()); err == nil {
+		return nil
+	}
+	// numbers with an exponent
+	f, err := n.Float64()
+	if err != nil {
+		return err
+	}
+	*m = MoneyFromFloat(f)
+	return nil
+}
+
+func repeatInt(n, count int) []int {
+	s := make([]int, count)
+	for i := range s {
+		s[i] = n
+	}
+	return s
+}
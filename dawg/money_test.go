@@ -0,0 +1,125 @@
+package dawg
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests.InitHelpers(t)
+	for _, tc := range []struct {
+		in  string
+		exp int64
+	}{
+		{"7.99", 799},
+		{" $7.99 ", 799},
+		{"1,299.00", 129900},
+		{"-0.5", -50},
+		{"-$1.50", -150},
+		{"12", 1200},
+		{"0.105", 11},
+		{"0.285", 29},
+		{"0.2849", 28},
+		{"-0.285", -29},
+		{".5", 50},
+		{"3.", 300},
+	} {
+		m, err := ParseMoney(tc.in)
+		tests.Check(err)
+		if m != Cents(tc.exp) {
+			t.Errorf("ParseMoney(%q) = %v, want %v", tc.in, m, Cents(tc.exp))
+		}
+	}
+	for _, bad := range []string{"", "$", ".", "abc", "1e3", "NaN", "1.2.3", "$-1", "1. 5", "99999999999999999999"} {
+		_, err := ParseMoney(bad)
+		tests.Exp(err, "should not parse", bad)
+	}
+}
+
+func TestMoneyFormat(t *testing.T) {
+	tests.InitHelpers(t)
+	tests.StrEq(Cents(799).String(), "$7.99", "wrong format")
+	tests.StrEq(Cents(-5).String(), "-$0.05", "wrong negative format")
+	tests.StrEq(Cents(120000).Decimal(), "1200.00", "wrong decimal")
+	tests.StrEq(Money{}.String(), "$0.00", "wrong zero format")
+	tests.StrEq(Cents(799).In("CAD").String(), "CA$7.99", "wrong canadian format")
+	tests.StrEq(Cents(-799).In("CAD").String(), "-CA$7.99", "wrong negative canadian format")
+	tests.StrEq(Cents(799).In("EUR").String(), "7.99 EUR", "wrong format for other currencies")
+}
+
+func TestMoneyMath(t *testing.T) {
+	tests.InitHelpers(t)
+	// these add up to 0.30000000000000004 with floats
+	if sum := MoneyFromFloat(0.1).Add(MoneyFromFloat(0.2)); sum != Cents(30) {
+		t.Errorf("got %v, want $0.30", sum)
+	}
+	if d := Cents(1000).Sub(Cents(1)); d != Cents(999) {
+		t.Errorf("got %v, want $9.99", d)
+	}
+	if p := Cents(799).Mul(3); p != Cents(2397) {
+		t.Errorf("got %v, want $23.97", p)
+	}
+	if (Money{}).Add(Cents(5)).Currency != DefaultCurrency {
+		t.Error("zero money should take the other currency")
+	}
+	if Cents(5).Cmp(Cents(6)) != -1 || Cents(6).Cmp(Cents(5)) != 1 || Cents(5).Cmp(Cents(5)) != 0 {
+		t.Error("bad comparison")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("adding different currencies should panic")
+			}
+		}()
+		Cents(1).Add(Money{Units: 1, Currency: "CAD"})
+	}()
+}
+
+func TestMoneySplit(t *testing.T) {
+	parts := Cents(1000).Split(3)
+	exp := []Money{Cents(334), Cents(333), Cents(333)}
+	for i := range exp {
+		if parts[i] != exp[i] {
+			t.Errorf("part %d: got %v, want %v", i, parts[i], exp[i])
+		}
+	}
+	parts = Cents(-1000).Split(3)
+	if parts[0].Add(parts[1]).Add(parts[2]) != Cents(-1000) {
+		t.Error("negative parts should add up to the original amount")
+	}
+	parts = Cents(1001).Allocate([]int{2, 0, 1})
+	if parts[0] != Cents(668) || parts[1] != Cents(0) || parts[2] != Cents(333) {
+		t.Errorf("wrong allocation: %v", parts)
+	}
+	for _, p := range Cents(5).Allocate([]int{0, 0}) {
+		if !p.IsZero() {
+			t.Error("zero weights should give zero parts")
+		}
+	}
+	if Cents(5).Split(0) != nil {
+		t.Error("splitting into zero parts should give nothing")
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	tests.InitHelpers(t)
+	var v struct {
+		A, B, C, D Money
+	}
+	tests.Check(json.Unmarshal([]byte(`{"A":"7.99","B":20.5,"C":"","D":null}`), &v))
+	if v.A != Cents(799) || v.B != Cents(2050) || !v.C.IsZero() || !v.D.IsZero() {
+		t.Errorf("wrong values: %+v", v)
+	}
+	tests.Check(json.Unmarshal([]byte(`{"A":0.285,"B":-1.5,"C":1e2}`), &v))
+	if v.A != Cents(29) || v.B != Cents(-150) || v.C != Cents(10000) {
+		t.Errorf("wrong values: %+v", v)
+	}
+	tests.Exp(json.Unmarshal([]byte(`{"A":"seven"}`), &v))
+	tests.Exp(json.Unmarshal([]byte(`{"A":true}`), &v))
+
+	b, err := json.Marshal(map[string]Money{"Amount": Cents(2450)})
+	tests.Check(err)
+	tests.StrEq(string(b), `{"Amount":24.50}`, "wrong json")
+}
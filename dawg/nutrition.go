@@ -0,0 +1,297 @@
+package dawg
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Allergens that are listed in the nutrition information.
+const (
+	AllergenMilk     = "milk"
+	AllergenEgg      = "egg"
+	AllergenWheat    = "wheat"
+	AllergenSoy      = "soy"
+	AllergenFish     = "fish"
+	AllergenPeanut   = "peanut"
+	AllergenTreeNuts = "tree nuts"
+)
+
+// Nutrition is the nutrition and allergen information for a menu item. It
+// does not include any toppings added to the item.
+type Nutrition struct {
+	// Calories is the number of calories in one serving.
+	Calories int
+
+	// Servings is the number of servings in one of the item, like the
+	// number of slices in a pizza.
+	Servings int
+
+	// Allergens are the allergens that the item contains.
+	Allergens []string
+
+	Vegetarian bool
+	GlutenFree bool
+}
+
+// TotalCalories is the number of calories in all of the servings.
+func (n Nutrition) TotalCalories() int {
+	if n.Servings < 1 {
+		return n.Calories
+	}
+	return n.Calories * n.Servings
+}
+
+// HasAllergen tells whether the item contains an allergen.
+func (n Nutrition) HasAllergen(allergen string) bool {
+	for _, a := range n.Allergens {
+		if strings.EqualFold(a, allergen) {
+			return true
+		}
+	}
+	return false
+}
+
+// NutritionFor finds the nutrition information for an item code in the
+// bundled table. It is used for items that dominos does not send any
+// nutrition information for.
+func NutritionFor(code string) (Nutrition, bool) {
+	n, ok := nutritionTable[code]
+	return n, ok
+}
+
+// Nutrition returns the nutrition information for the item. The item's tags
+// from the menu are used first and anything missing comes from the bundled
+// table (see NutritionFor). Returns false if there is no information for the
+// item.
+func (im *ItemCommon) Nutrition() (Nutrition, bool) {
+	n, ok := NutritionFor(im.Code)
+	if im.Tags.nutrition(&n) {
+		ok = true
+	}
+	return n, ok
+}
+
+// Nutrition returns the nutrition information for the variant, using the
+// information for its product if there is none for the variant itself.
+func (v *Variant) Nutrition() (Nutrition, bool) {
+	n, ok := v.ItemCommon.Nutrition()
+	if !ok && v.product != nil {
+		return v.product.Nutrition()
+	}
+	return n, ok
+}
+
+// nutrition fills in the nutrition information found in the tags and returns
+// true if there was any.
+func (t ItemTags) nutrition(n *Nutrition) (found bool) {
+	if cal, ok := t.Number("Calories"); ok {
+		n.Calories, found = int(cal), true
+	}
+	if servings, ok := t.Number("Servings"); ok {
+		n.Servings, found = int(servings), true
+	}
+	if _, ok := t["Vegetarian"]; ok {
+		n.Vegetarian, found = t.Bool("Vegetarian"), true
+	}
+	if _, ok := t["GlutenFree"]; ok {
+		n.GlutenFree, found = t.Bool("GlutenFree"), true
+	}
+	if _, ok := t["Allergens"]; ok {
+		n.Allergens, found = nil, true
+		for _, a := range t.Strings("Allergens") {
+			for _, s := range strings.Split(a, ",") {
+				if s = strings.ToLower(strings.TrimSpace(s)); s != "" {
+					n.Allergens = append(n.Allergens, s)
+				}
+			}
+		}
+	}
+	return found
+}
+
+// Number returns a tag's value if it is a number or a string holding a
+// number.
+func (t ItemTags) Number(key string) (float64, bool) {
+	switch val := t[key].(type) {
+	case float64:
+		return val, true
+	case int:
+		return float64(val), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// OrderNutrition is the nutrition information for a whole order.
+type OrderNutrition struct {
+	// Calories is the total number of calories in the order.
+	Calories int
+
+	// Allergens are all the allergens in the order.
+	Allergens []string
+
+	// Vegetarian and GlutenFree are only true if they are true for every
+	// product in the order.
+	Vegetarian bool
+	GlutenFree bool
+
+	// Unknown are the codes of the products that there is no nutrition
+	// information for, or that have a topping with no information. Products
+	// with no information at all are not part of the totals.
+	Unknown []string
+}
+
+// Nutrition adds up the nutrition information of the products in the order.
+// The toppings on each product are checked for allergens and diets, but their
+// calories are not included.
+func (o *Order) Nutrition() *OrderNutrition {
+	on := &OrderNutrition{Vegetarian: true, GlutenFree: true}
+	allergens := make(map[string]bool)
+	for _, p := range o.Products {
+		n, ok := p.Nutrition()
+		if !ok {
+			on.Unknown = append(on.Unknown, p.Code)
+			continue
+		}
+		qty := p.Qty
+		if qty < 1 {
+			qty = 1
+		}
+		on.Calories += n.TotalCalories() * qty
+		on.Vegetarian = on.Vegetarian && n.Vegetarian
+		on.GlutenFree = on.GlutenFree && n.GlutenFree
+		for _, a := range n.Allergens {
+			allergens[a] = true
+		}
+
+		for _, code := range p.toppings() {
+			top, ok := ToppingNutrition(code)
+			if !ok {
+				on.Unknown = append(on.Unknown, p.Code)
+				break
+			}
+			on.Vegetarian = on.Vegetarian && top.Vegetarian
+			on.GlutenFree = on.GlutenFree && top.GlutenFree
+			for _, a := range top.Allergens {
+				allergens[a] = true
+			}
+		}
+	}
+	if len(on.Unknown) > 0 || len(o.Products) == 0 {
+		on.Vegetarian, on.GlutenFree = false, false
+	}
+	on.Allergens = sortedKeys(allergens)
+	return on
+}
+
+// ToppingNutrition finds the allergen and diet information for a topping code
+// in the bundled table. The calories of toppings are not known.
+func ToppingNutrition(code string) (Nutrition, bool) {
+	n, ok := toppingTable[code]
+	return n, ok
+}
+
+// toppings returns the codes of the toppings that are on the product. Toppings
+// with an amount of zero have been taken off and are left out.
+func (p *OrderProduct) toppings() []string {
+	var codes []string
+	for code, top := range p.Opts {
+		if hasTopping(top) {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+func hasTopping(top interface{}) bool {
+	var amounts []string
+	switch t := top.(type) {
+	case map[string]string:
+		for _, amnt := range t {
+			amounts = append(amounts, amnt)
+		}
+	case map[string]interface{}:
+		for _, amnt := range t {
+			amounts = append(amounts, fmt.Sprint(amnt))
+		}
+	default:
+		return true
+	}
+	for _, amnt := range amounts {
+		if f, err := strconv.ParseFloat(amnt, 64); err != nil || f != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// nutritionTable is the fallback nutrition information for common items on
+// the US menu. The numbers are approximate values taken from the dominos
+// nutrition guide and only cover the crust, sauce, and cheese. Only items
+// without any wheat are marked gluten free. Always check with the store when
+// an allergy is serious.
+var nutritionTable = map[string]Nutrition{
+	// pizza, per slice
+	"10SCREEN": {Calories: 190, Servings: 6, Allergens: []string{AllergenMilk, AllergenWheat, AllergenSoy}, Vegetarian: true},
+	"12SCREEN": {Calories: 200, Servings: 8, Allergens: []string{AllergenMilk, AllergenWheat, AllergenSoy}, Vegetarian: true},
+	"14SCREEN": {Calories: 290, Servings: 8, Allergens: []string{AllergenMilk, AllergenWheat, AllergenSoy}, Vegetarian: true},
+	"10THIN":   {Calories: 110, Servings: 6, Allergens: []string{AllergenMilk, AllergenWheat, AllergenSoy}, Vegetarian: true},
+	"12THIN":   {Calories: 140, Servings: 8, Allergens: []string{AllergenMilk, AllergenWheat, AllergenSoy}, Vegetarian: true},
+	"14THIN":   {Calories: 190, Servings: 8, Allergens: []string{AllergenMilk, AllergenWheat, AllergenSoy}, Vegetarian: true},
+	"P12IPAZA": {Calories: 230, Servings: 8, Allergens: []string{AllergenMilk, AllergenWheat, AllergenSoy}, Vegetarian: true},
+	"P10IGFZA": {Calories: 160, Servings: 6, Allergens: []string{AllergenMilk, AllergenWheat, AllergenSoy}, Vegetarian: true},
+
+	// breads and desserts, per piece
+	"B8PCSCB":  {Calories: 140, Servings: 8, Allergens: []string{AllergenMilk, AllergenWheat, AllergenSoy}, Vegetarian: true},
+	"B8PCGT":   {Calories: 110, Servings: 8, Allergens: []string{AllergenMilk, AllergenWheat, AllergenSoy}, Vegetarian: true},
+	"B16PBIT":  {Calories: 40, Servings: 16, Allergens: []string{AllergenMilk, AllergenWheat, AllergenSoy}, Vegetarian: true},
+	"B2PCLAVA": {Calories: 350, Servings: 2, Allergens: []string{AllergenMilk, AllergenEgg, AllergenWheat, AllergenSoy}, Vegetarian: true},
+
+	// drinks, per bottle or 8 oz
+	"20BCOKE":  {Calories: 240, Servings: 1, Vegetarian: true, GlutenFree: true},
+	"20BDCOKE": {Calories: 0, Servings: 1, Vegetarian: true, GlutenFree: true},
+	"2LCOKE":   {Calories: 100, Servings: 8, Vegetarian: true, GlutenFree: true},
+	"2LDCOKE":  {Calories: 0, Servings: 8, Vegetarian: true, GlutenFree: true},
+	"BOTTLWTR": {Calories: 0, Servings: 1, Vegetarian: true, GlutenFree: true},
+}
+
+// toppingTable is the allergen and diet information for common toppings on
+// the US menu. Toppings that are not in the table make the product they are on
+// unknown.
+var toppingTable = map[string]Nutrition{
+	// sauces and cheeses
+	"X":  {Vegetarian: true, GlutenFree: true},
+	"Xm": {Allergens: []string{AllergenMilk}, Vegetarian: true, GlutenFree: true},
+	"Xw": {Allergens: []string{AllergenMilk, AllergenEgg, AllergenSoy}, Vegetarian: true},
+	"C":  {Allergens: []string{AllergenMilk}, Vegetarian: true, GlutenFree: true},
+	"E":  {Allergens: []string{AllergenMilk}, Vegetarian: true, GlutenFree: true},
+	"Fe": {Allergens: []string{AllergenMilk}, Vegetarian: true, GlutenFree: true},
+	"Cp": {Allergens: []string{AllergenMilk}, Vegetarian: true, GlutenFree: true},
+
+	// vegetables
+	"G":  {Vegetarian: true, GlutenFree: true},
+	"M":  {Vegetarian: true, GlutenFree: true},
+	"N":  {Vegetarian: true, GlutenFree: true},
+	"O":  {Vegetarian: true, GlutenFree: true},
+	"R":  {Vegetarian: true, GlutenFree: true},
+	"J":  {Vegetarian: true, GlutenFree: true},
+	"Z":  {Vegetarian: true, GlutenFree: true},
+	"Td": {Vegetarian: true, GlutenFree: true},
+	"Si": {Vegetarian: true, GlutenFree: true},
+	"Rr": {Vegetarian: true, GlutenFree: true},
+
+	// meats, which are not marked gluten free to be safe
+	"P":  {},
+	"S":  {},
+	"B":  {},
+	"H":  {},
+	"K":  {},
+	"Pm": {},
+	"Sa": {},
+	"Du": {},
+}
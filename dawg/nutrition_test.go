@@ -0,0 +1,115 @@
+package dawg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestNutrition(t *testing.T) {
+	tests.InitHelpers(t)
+	n, ok := NutritionFor("14SCREEN")
+	if !ok {
+		t.Fatal("14SCREEN should be in the nutrition table")
+	}
+	if n.TotalCalories() != n.Calories*n.Servings {
+		t.Error("wrong total calories")
+	}
+	if !n.HasAllergen("Wheat") || n.HasAllergen(AllergenPeanut) {
+		t.Error("wrong allergens")
+	}
+	if _, ok = NutritionFor("NOPE"); ok {
+		t.Error("unknown codes should not have nutrition information")
+	}
+	if n, _ = NutritionFor("P10IGFZA"); n.GlutenFree || !n.HasAllergen(AllergenWheat) {
+		t.Error("P10IGFZA has a wheat crust")
+	}
+	for _, table := range []map[string]Nutrition{nutritionTable, toppingTable} {
+		for code, n := range table {
+			if n.GlutenFree && n.HasAllergen(AllergenWheat) {
+				t.Errorf("%s has wheat but is marked gluten free", code)
+			}
+		}
+	}
+
+	// the menu's tags are used over the table
+	v := &Variant{ItemCommon: ItemCommon{Code: "14SCREEN", Tags: ItemTags{
+		"Calories":  "300",
+		"Allergens": []interface{}{"Milk, Wheat", "Egg"},
+	}}}
+	n, ok = v.Nutrition()
+	if !ok || n.Calories != 300 || n.Servings != 8 || !n.Vegetarian {
+		t.Errorf("wrong nutrition: %+v", n)
+	}
+	if !reflect.DeepEqual(n.Allergens, []string{"milk", "wheat", "egg"}) {
+		t.Errorf("wrong allergens: %v", n.Allergens)
+	}
+
+	// variants use their product's information when they have none
+	v = &Variant{
+		ItemCommon: ItemCommon{Code: "W08PHOTW"},
+		product:    &Product{ItemCommon: ItemCommon{Tags: ItemTags{"GlutenFree": true}}},
+	}
+	if n, ok = v.Nutrition(); !ok || !n.GlutenFree {
+		t.Errorf("should have used the product's nutrition: %+v", n)
+	}
+	if _, ok = (&Variant{ItemCommon: ItemCommon{Code: "W08PHOTW"}}).Nutrition(); ok {
+		t.Error("should not find nutrition information")
+	}
+}
+
+func TestOrderNutrition(t *testing.T) {
+	o := &Order{}
+	if n := o.Nutrition(); n.Vegetarian || n.Calories != 0 {
+		t.Error("an empty order should not have any nutrition")
+	}
+	o.Products = []*OrderProduct{
+		{ItemCommon: ItemCommon{Code: "12SCREEN"}, Qty: 2},
+		{ItemCommon: ItemCommon{Code: "20BCOKE"}, Qty: 1},
+	}
+	n := o.Nutrition()
+	if n.Calories != 2*8*200+240 {
+		t.Errorf("wrong calories: %d", n.Calories)
+	}
+	if !reflect.DeepEqual(n.Allergens, []string{"milk", "soy", "wheat"}) {
+		t.Errorf("wrong allergens: %v", n.Allergens)
+	}
+	if !n.Vegetarian || n.GlutenFree {
+		t.Error("wrong diet")
+	}
+
+	o.Products = append(o.Products, &OrderProduct{ItemCommon: ItemCommon{Code: "W08PHOTW"}, Qty: 1})
+	n = o.Nutrition()
+	if len(n.Unknown) != 1 || n.Unknown[0] != "W08PHOTW" {
+		t.Errorf("wrong unknown products: %v", n.Unknown)
+	}
+	if n.Vegetarian {
+		t.Error("an order with unknown products should not be vegetarian")
+	}
+
+	pizza := &OrderProduct{ItemCommon: ItemCommon{Code: "14SCREEN"}, Qty: 1, Opts: map[string]interface{}{
+		"X": map[string]string{ToppingFull: "1.0"},
+		"C": map[string]interface{}{ToppingFull: "1.0"},
+	}}
+	o.Products = []*OrderProduct{pizza}
+	if n = o.Nutrition(); !n.Vegetarian || len(n.Unknown) != 0 {
+		t.Errorf("a cheese pizza should be vegetarian: %+v", n)
+	}
+	pizza.Opts["P"] = map[string]string{ToppingFull: "1.0"}
+	if n = o.Nutrition(); n.Vegetarian || n.GlutenFree {
+		t.Errorf("a pepperoni pizza should not be vegetarian: %+v", n)
+	}
+	if n.Calories != 8*290 {
+		t.Errorf("wrong calories: %d", n.Calories)
+	}
+	pizza.Opts["P"] = map[string]string{ToppingFull: "0.0"}
+	if n = o.Nutrition(); !n.Vegetarian {
+		t.Error("toppings that were taken off should not count")
+	}
+	pizza.Opts["ZZ"] = map[string]string{ToppingLeft: "1.0"}
+	n = o.Nutrition()
+	if n.Vegetarian || len(n.Unknown) != 1 || n.Unknown[0] != "14SCREEN" {
+		t.Errorf("unknown toppings should make the product unknown: %+v", n)
+	}
+}
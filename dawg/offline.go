@@ -0,0 +1,34 @@
+package dawg
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrOffline is returned by anything that needs to connect to dominos while
+// offline mode is on (see SetOffline).
+var ErrOffline = errors.New("dawg: not connecting to dominos in offline mode")
+
+var offline int32
+
+// SetOffline turns offline mode on or off. While it is on, every request to
+// dominos fails right away with ErrOffline instead of going over the network.
+func SetOffline(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&offline, v)
+}
+
+// IsOffline returns true if offline mode is on.
+func IsOffline() bool {
+	return atomic.LoadInt32(&offline) == 1
+}
+
+func checkOffline() error {
+	if IsOffline() {
+		return ErrOffline
+	}
+	return nil
+}
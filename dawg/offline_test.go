@@ -0,0 +1,37 @@
+package dawg
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOffline(t *testing.T) {
+	SetOffline(true)
+	defer SetOffline(false)
+	if !IsOffline() {
+		t.Fatal("should be offline")
+	}
+
+	start := time.Now()
+	if _, err := NearestStore(testAddress(), Delivery); err != ErrOffline {
+		t.Errorf("expected ErrOffline; got %v", err)
+	}
+	if _, err := Canada.NearestStore(testAddress(), Carryout); err != ErrOffline {
+		t.Errorf("expected ErrOffline; got %v", err)
+	}
+	if _, err := SignIn("user", "pass"); err != ErrOffline {
+		t.Errorf("expected ErrOffline; got %v", err)
+	}
+	if _, err := orderClient.Get("https://order.dominos.com/"); !errors.Is(err, ErrOffline) {
+		t.Errorf("requests that skip the client methods should still fail; got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("offline mode should fail fast")
+	}
+
+	SetOffline(false)
+	if IsOffline() {
+		t.Error("should not be offline")
+	}
+}
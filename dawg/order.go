@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
 )
 
 // TODO: alphabetize the Order struct fields and add some more documentation
@@ -20,7 +22,7 @@ type Order struct {
 	// LanguageCode is an ISO international language code.
 	LanguageCode string `json:"LanguageCode"`
 
-	ServiceMethod string                 `json:"ServiceMethod"`
+	ServiceMethod ServiceMethod          `json:"ServiceMethod"`
 	Products      []*OrderProduct        `json:"Products"`
 	StoreID       string                 `json:"StoreID"`
 	OrderID       string                 `json:"OrderID"`
@@ -31,12 +33,30 @@ type Order struct {
 	Email         string                 `json:"Email"`
 	Phone         string
 	Payments      []*orderPayment `json:"Payments"`
+	Coupons       []*OrderCoupon  `json:"Coupons,omitempty"`
+
+	// PulseOrderGUID is the id that the store's system gives an order once
+	// it has been placed.
+	PulseOrderGUID string `json:"-"`
+
+	// Vehicle is the customer's car, which is needed for drive-up carryout
+	// orders (see ServiceMethod.NeedsVehicle).
+	Vehicle *Vehicle `json:"Vehicle,omitempty"`
+
+	// FutureOrderTime is the time that the order is scheduled for in the
+	// FutureTimeFormat. Orders with no future time are made right away (see
+	// SetFutureTime).
+	FutureOrderTime string `json:"FutureOrderTime,omitempty"`
 
 	// OrderName is not a field that is sent to dominos, but is just a way for
 	// users to name a specific order.
 	OrderName string `json:"-"`
-	price     float64
+	price     Money
+	tip       Money
+	amounts   map[string]Money
+	points    *int // points spent from the price response
 	cli       *client
+	user      *UserProfile
 }
 
 // InitOrder will make sure that an order is initialized correctly. An order
@@ -57,22 +77,50 @@ func (o *Order) init() {
 
 // PlaceOrder is the method that sends the final order to dominos
 func (o *Order) PlaceOrder() error {
+	if err := o.checkService(); err != nil {
+		return err
+	}
 	if err := o.prepare(); err != nil {
 		return err
 	}
-	return sendOrder("/power/place-order", *o)
+	b, err := o.cli.post("/power/place-order", nil, o.raw())
+	if err != nil {
+		return err
+	}
+	resp := &priceingData{}
+	if json.Unmarshal(b, resp) == nil {
+		if resp.Order.OrderID != "" {
+			o.OrderID = resp.Order.OrderID
+		}
+		o.PulseOrderGUID = resp.Order.PulseOrderGUID
+	}
+	return dominosErr(b)
 }
 
 // Price method returns the total price of an order.
-func (o *Order) Price() (float64, error) {
-	if o.price == 0.0 {
+func (o *Order) Price() (Money, error) {
+	if o.price.IsZero() {
 		if err := o.prepare(); err != nil {
-			return -1.0, err
+			return Money{}, err
 		}
 	}
 	return o.price, nil
 }
 
+// PricedAmounts returns the amounts that dominos gave the order the last time it
+// was priced, like "Customer" (the total), "Tax", and "Payment". Returns nil if
+// the order has not been priced.
+func (o *Order) PricedAmounts() map[string]Money {
+	if o.amounts == nil {
+		return nil
+	}
+	amounts := make(map[string]Money, len(o.amounts))
+	for k, v := range o.amounts {
+		amounts[k] = v
+	}
+	return amounts
+}
+
 // AddProduct adds a product to the Order from a Product Object
 func (o *Order) AddProduct(item Item) error {
 	if item == nil {
@@ -127,6 +175,155 @@ func (o *Order) AddCard(c Card) {
 	o.Payments = append(o.Payments, makeOrderPaymentFromCard(c))
 }
 
+// SetUser will make the order belong to a signed in user. All requests made
+// by the order will use the user's credentials. This is needed for orders
+// that redeem loyalty rewards.
+func (o *Order) SetUser(u *UserProfile) {
+	o.user = u
+	if o.CustomerID == "" {
+		o.CustomerID = u.CustomerID
+	}
+	if u.auth != nil {
+		o.cli = u.auth.cli
+	}
+}
+
+// RedeemLoyalty will add a loyalty coupon to the order that is paid for using
+// the user's loyalty points. The order must belong to a signed in user (see
+// UserProfile.NewOrder or Order.SetUser) whose vested point balance covers
+// the points already being redeemed with the order plus the points for this
+// coupon.
+func (o *Order) RedeemLoyalty(couponCode string) error {
+	if o.user == nil {
+		return errors.New("only orders from a signed in user can redeem loyalty points")
+	}
+	loyalty, err := o.user.getLoyalty()
+	if err != nil {
+		return err
+	}
+
+	var coupon *LoyaltyCoupon
+	for i := range loyalty.LoyaltyCoupons {
+		if loyalty.LoyaltyCoupons[i].CouponCode == couponCode {
+			coupon = &loyalty.LoyaltyCoupons[i]
+			break
+		}
+	}
+	if coupon == nil {
+		return fmt.Errorf("'%s' is not a loyalty coupon", couponCode)
+	}
+
+	limit, err := strconv.Atoi(coupon.LimitPerOrder)
+	if err == nil && limit > 0 {
+		n := 0
+		for _, c := range o.Coupons {
+			if c.Code == couponCode {
+				n += c.Qty
+			}
+		}
+		if n >= limit {
+			return fmt.Errorf("coupon '%s' can only be used %d time(s) per order", couponCode, limit)
+		}
+	}
+
+	points := o.loyaltyPoints() + coupon.PointValue
+	if points > loyalty.VestedPointBalance {
+		return fmt.Errorf("not enough loyalty points: have %d, need %d",
+			loyalty.VestedPointBalance, points)
+	}
+	o.Coupons = append(o.Coupons, &OrderCoupon{
+		Code:            couponCode,
+		Qty:             1,
+		ID:              len(o.Coupons) + 1,
+		IsNew:           true,
+		IsLoyaltyCoupon: true,
+		IsAutoApplied:   false,
+		PointValue:      coupon.PointValue,
+	})
+	o.price, o.points = Money{}, nil // the price has changed
+	return nil
+}
+
+// PointsRedeemed returns the number of loyalty points that will be spent
+// by the order. Once the order has been priced this is the number of points
+// that dominos sent back, and before that (or if dominos did not send any) it
+// is added up from the loyalty coupons on the order.
+func (o *Order) PointsRedeemed() int {
+	if o.points != nil {
+		return *o.points
+	}
+	return o.loyaltyPoints()
+}
+
+// loyaltyPoints adds up the points of the loyalty coupons on the order.
+func (o *Order) loyaltyPoints() (points int) {
+	for _, c := range o.Coupons {
+		if c.IsLoyaltyCoupon {
+			points += c.PointValue * c.Qty
+		}
+	}
+	return points
+}
+
+// SetTip sets the tip for the delivery driver. The tip is added to the amount
+// charged to the order's payments when the order is placed, but it is not
+// part of the order's price. The tip has to be in the currency of the order's
+// market.
+func (o *Order) SetTip(amount Money) error {
+	if amount.Units < 0 {
+		return errors.New("tip cannot be negative")
+	}
+	cur := o.Market().Currency
+	if amount.Currency != "" && amount.Currency != cur {
+		return fmt.Errorf("the tip is in %s but the order is paid in %s", amount.Currency, cur)
+	}
+	o.tip = amount.In(cur)
+	return nil
+}
+
+// Tip returns the tip for the delivery driver.
+func (o *Order) Tip() Money {
+	return o.tip
+}
+
+// SetDeliveryInstructions sets the notes for the delivery driver. The
+// instructions are sent with the order's address.
+func (o *Order) SetDeliveryInstructions(instructions string) error {
+	if o.Address == nil {
+		return errors.New("order has no address for delivery instructions")
+	}
+	o.Address.DeliveryInstructions = instructions
+	return nil
+}
+
+// SetFutureTime schedules the order for a time in the future. The time is
+// checked against the store's hours for the order's service method and should
+// be in the store's time zone. A zero time will make the order right away.
+func (o *Order) SetFutureTime(store *Store, t time.Time) error {
+	if t.IsZero() {
+		o.FutureOrderTime = ""
+		return nil
+	}
+	if store == nil || store.ID != o.StoreID {
+		return errors.New("future order time must be checked against the order's store")
+	}
+	if err := store.ValidateFutureTime(o.ServiceMethod, t); err != nil {
+		return err
+	}
+	o.FutureOrderTime = t.Format(FutureTimeFormat)
+	return nil
+}
+
+// FutureTime returns the time that the order is scheduled for in the local
+// time zone. The time is zero if the order is not scheduled.
+func (o *Order) FutureTime() time.Time {
+	t, err := time.ParseInLocation(FutureTimeFormat, o.FutureOrderTime, time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
 // Name returns the name that was set by the user.
 func (o *Order) Name() string {
 	return o.OrderName
@@ -154,14 +351,29 @@ func (o *Order) prepare() error {
 		return err
 	}
 	o.OrderID = odata.Order.OrderID
+	o.amounts = odata.Order.Amounts
+	o.points = nil
+	if points, ok := odata.Order.pointsRedeemed(); ok {
+		o.points = &points
+	}
+	for k, amount := range o.amounts {
+		o.amounts[k] = amount.In(o.Market().Currency)
+	}
 
-	p, ok := odata.Order.Amounts["Customer"]
+	p, ok := o.amounts["Customer"]
 	if ok {
 		o.price = p
 
+		// split the price and the tip evenly between the cards so that the
+		// payments add up to exactly the total
 		n := len(o.Payments)
+		prices, tips := p.Split(n), o.tip.Split(n)
 		for i := 0; i < n; i++ {
-			o.Payments[i].Amount = p
+			o.Payments[i].Amount = prices[i].Add(tips[i])
+			o.Payments[i].TipAmount = nil
+			if !tips[i].IsZero() {
+				o.Payments[i].TipAmount = &tips[i]
+			}
 		}
 	}
 	return nil
@@ -247,9 +459,51 @@ type priceingData struct {
 
 type pricedOrder struct {
 	OrderID          string
-	Amounts          map[string]float64
+	Amounts          map[string]Money
 	AmountsBreakdown map[string]interface{}
 	PulseOrderGUID   string `json:"PulseOrderGuid"`
+
+	// Coupons are the coupons that dominos priced, and loyalty coupons have
+	// the points that they cost.
+	Coupons []*OrderCoupon
+	// Loyalty is sent for orders from a signed in user.
+	Loyalty *struct {
+		PointsRedeemed *int
+	}
+}
+
+// pointsRedeemed finds the number of points spent in the price response.
+// Returns false if the response does not have any.
+func (po *pricedOrder) pointsRedeemed() (int, bool) {
+	if po.Loyalty != nil && po.Loyalty.PointsRedeemed != nil {
+		return *po.Loyalty.PointsRedeemed, true
+	}
+	var (
+		points int
+		found  bool
+	)
+	for _, c := range po.Coupons {
+		if c.IsLoyaltyCoupon && c.PointValue > 0 {
+			points += c.PointValue * c.Qty
+			found = true
+		}
+	}
+	return points, found
+}
+
+// OrderCoupon is a coupon that is sent to dominos with an order.
+type OrderCoupon struct {
+	Code  string `json:"Code"`
+	Qty   int    `json:"Qty"`
+	ID    int    `json:"ID"`
+	IsNew bool   `json:"IsNew"`
+
+	// IsLoyaltyCoupon is true when the coupon is paid for with loyalty points.
+	IsLoyaltyCoupon bool `json:"IsLoyaltyCoupon"`
+	IsAutoApplied   bool `json:"IsAutoApplied"`
+
+	// PointValue is the number of loyalty points the coupon costs.
+	PointValue int `json:"PointValue,omitempty"`
 }
 
 // OrderProduct represents an item that will be sent to and from dominos within
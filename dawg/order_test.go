@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"strings"
 	"testing"
 	"time"
@@ -102,7 +103,7 @@ func TestNewOrder(t *testing.T) {
 	if IsFailure(err) {
 		t.Error(err)
 	}
-	if price == -1.0 {
+	if price.IsZero() {
 		t.Error("Order.Price() failed")
 	}
 	tests.Check(err)
@@ -115,7 +116,7 @@ func TestPrepareOrder(t *testing.T) {
 	tests.StrEq(o.FirstName, "Bob", "wrong first name")
 	tests.StrEq(o.LastName, "Smith", "wrong last name")
 	tests.StrEq(o.Email, "bobsmith@aol.com", "wrong email")
-	if o.price > 0.0 {
+	if !o.price.IsZero() {
 		t.Error("order should not be initialized with a price above zero")
 	}
 	if len(o.OrderID) != 0 {
@@ -125,7 +126,7 @@ func TestPrepareOrder(t *testing.T) {
 	menu := testingMenu()
 	tests.Check(o.AddProduct(menu.FindItem("10SCREEN")))
 	tests.Check(o.prepare())
-	if o.price <= 0.0 {
+	if o.price.Units <= 0 {
 		t.Error("cached price should not be zero or less")
 	}
 	if len(o.OrderID) == 0 {
@@ -157,7 +158,7 @@ func TestOrder_Err(t *testing.T) {
 	tests.Check(o.AddProduct(v))
 	price, err := o.Price()
 	tests.Exp(err)
-	if price != -1.0 {
+	if !price.IsZero() {
 		t.Error("expected bad price")
 	}
 	tests.Exp(o.AddProduct(nil))
@@ -369,3 +370,131 @@ func TestCardTypeRegex(t *testing.T) {
 		}
 	}
 }
+
+func TestRedeemLoyalty(t *testing.T) {
+	tests.InitHelpers(t)
+	user := &UserProfile{CustomerID: "123"}
+	user.loyaltyData = &CustomerLoyalty{
+		VestedPointBalance: 75,
+		LoyaltyCoupons: []LoyaltyCoupon{
+			{CouponCode: "8000", PointValue: 60, LimitPerOrder: "1"},
+			{CouponCode: "8001", PointValue: 10, LimitPerOrder: "2"},
+		},
+	}
+	o := &Order{}
+	tests.Exp(o.RedeemLoyalty("8000"), "an order without a user cannot redeem points")
+	o.SetUser(user)
+	tests.StrEq(o.CustomerID, user.CustomerID, "SetUser should set the customer id")
+
+	tests.Exp(o.RedeemLoyalty("nothere"))
+	tests.Check(o.RedeemLoyalty("8000"))
+	tests.Exp(o.RedeemLoyalty("8000"), "should only be able to use 8000 once")
+	tests.Check(o.RedeemLoyalty("8001"))
+	tests.Exp(o.RedeemLoyalty("8001"), "should not have enough points")
+	if o.PointsRedeemed() != 70 {
+		t.Errorf("wrong number of points redeemed: got %d, want 70", o.PointsRedeemed())
+	}
+	if len(o.Coupons) != 2 {
+		t.Fatal("wrong number of coupons")
+	}
+	c := o.Coupons[1]
+	if !c.IsLoyaltyCoupon || c.Code != "8001" || c.ID != 2 || c.Qty != 1 {
+		t.Errorf("bad loyalty coupon: %+v", c)
+	}
+
+	raw, err := json.Marshal(o)
+	tests.Check(err)
+	saved := &Order{}
+	tests.Check(json.Unmarshal(raw, saved))
+	if saved.PointsRedeemed() != 70 {
+		t.Error("points should be remembered after the order is stored")
+	}
+
+	// the points from the price response are used once the order is priced
+	for _, tc := range []struct {
+		body string
+		exp  int
+	}{
+		{`{"Status":0,"Order":{"Amounts":{"Customer":5},"Loyalty":{"PointsRedeemed":60}}}`, 60},
+		{`{"Status":0,"Order":{"Amounts":{"Customer":5},"Coupons":[
+			{"Code":"8000","Qty":1,"IsLoyaltyCoupon":true,"PointValue":60},
+			{"Code":"8001","Qty":2,"IsLoyaltyCoupon":true,"PointValue":10}]}}`, 80},
+		{`{"Status":0,"Order":{"Amounts":{"Customer":5}}}`, 70},
+	} {
+		body := tc.body
+		o.cli = &client{host: orderHost, Client: &http.Client{
+			Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+			}),
+		}}
+		tests.Check(o.prepare())
+		if o.PointsRedeemed() != tc.exp {
+			t.Errorf("wrong points from the price response: got %d, want %d", o.PointsRedeemed(), tc.exp)
+		}
+	}
+}
+
+func TestOrder_Tip(t *testing.T) {
+	tests.InitHelpers(t)
+	var sent string
+	o := &Order{StoreID: "4336", ServiceMethod: Delivery, Address: &StreetAddr{
+		Street: "1600 Pennsylvania Ave NW", CityName: "Washington", State: "DC", Zipcode: "20500",
+		UnitType: "Suite", UnitNumber: "100",
+	}}
+	o.cli = &client{host: orderHost, Client: &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			b, err := ioutil.ReadAll(r.Body)
+			tests.Check(err)
+			sent = string(b)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: ioutil.NopCloser(strings.NewReader(
+					`{"Status":0,"Order":{"Status":0,"OrderID":"abc","Amounts":{"Customer":20.5}}}`)),
+			}, nil
+		}),
+	}}
+	o.AddCard(NewCard("4111111111111111", "01/30", 123))
+
+	tests.Exp(o.SetTip(Cents(-100)), "tips cannot be negative")
+	tests.Check(o.SetTip(Cents(400)))
+	tests.Check(o.SetDeliveryInstructions("leave it at the front desk"))
+	price, err := o.Price()
+	tests.Check(err)
+	if price != Cents(2050) {
+		t.Errorf("the tip should not be part of the price; got %v", price)
+	}
+	if o.Payments[0].Amount != Cents(2450) || o.Payments[0].TipAmount == nil || *o.Payments[0].TipAmount != Cents(400) {
+		t.Errorf("the tip should be charged to the card; got %v and %v",
+			o.Payments[0].Amount, o.Payments[0].TipAmount)
+	}
+	if js := OrderToJSON(o); !strings.Contains(js, `"Amount": 24.50`) || !strings.Contains(js, `"TipAmount": 4.00`) {
+		t.Errorf("the payment amounts should be sent as numbers:\n%s", js)
+	}
+	for _, s := range []string{`"UnitType":"Suite"`, `"UnitNumber":"100"`, `"DeliveryInstructions":"leave it at the front desk"`} {
+		if !strings.Contains(sent, s) {
+			t.Errorf("%s should be sent to dominos", s)
+		}
+	}
+
+	// split payments have to add up to exactly the total
+	o.AddCard(NewCard("4111111111111111", "01/30", 123))
+	o.AddCard(NewCard("4111111111111111", "01/30", 123))
+	tests.Check(o.SetTip(Cents(100)))
+	tests.Check(o.prepare())
+	var total, tip Money
+	for _, p := range o.Payments {
+		total = total.Add(p.Amount)
+		if p.TipAmount != nil {
+			tip = tip.Add(*p.TipAmount)
+		}
+	}
+	if total != Cents(2150) || tip != Cents(100) {
+		t.Errorf("split payments should add up to the total; got %v with a %v tip", total, tip)
+	}
+	if o.Payments[0].Amount != Cents(684+34) || o.Payments[2].Amount != Cents(683+33) {
+		t.Errorf("wrong split: %v, %v, %v", o.Payments[0].Amount, o.Payments[1].Amount, o.Payments[2].Amount)
+	}
+
+	o.Address = nil
+	tests.Exp(o.SetDeliveryInstructions("ring twice"), "needs an address")
+}
@@ -0,0 +1,441 @@
+package dawg
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// AddressError is returned by ParseAddress when one of the fields of an
+// address is missing or cannot be understood.
+type AddressError struct {
+	// Field is the part of the address with the problem, like "street
+	// number", "city", "region", or "postal code".
+	Field string
+	// Value is the text that was being parsed as the field.
+	Value string
+	Msg   string
+}
+
+func (e *AddressError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("address %s: %s", e.Field, e.Msg)
+	}
+	return fmt.Sprintf("address %s %q: %s", e.Field, e.Value, e.Msg)
+}
+
+// ParseAddress will parse a raw address and return an address object.
+//
+// Addresses look like "123 N Main St Apt 4, Springfield, IL 62704-1234". The
+// commas are optional and the parts of the address can also be on separate
+// lines. Street types and directionals are shortened ("Street" becomes "St"
+// and "North" becomes "N"), and units like "Apt 4" or "#4" are put in the
+// UnitType and UnitNumber fields. Zip codes can have the extra four digits
+// and canadian postal codes are understood too.
+//
+// An *AddressError is returned if any part of the address is missing or
+// cannot be understood.
+func ParseAddress(raw string) (*StreetAddr, error) {
+	p := &addrParser{toks: tokenizeAddr(raw)}
+	if len(p.toks) == 0 {
+		return nil, &AddressError{Field: "address", Msg: "address is empty"}
+	}
+	addr := &StreetAddr{}
+	var err error
+	// the end of the address is parsed first so that the street knows where
+	// to stop when there are no commas.
+	p.end = len(p.toks)
+	p.dropCountry()
+	if addr.Zipcode, err = p.postalCode(); err != nil {
+		return nil, err
+	}
+	if addr.State, err = p.region(); err != nil {
+		return nil, err
+	}
+	if err = p.street(addr); err != nil {
+		return nil, err
+	}
+	if err = p.unit(addr); err != nil {
+		return nil, err
+	}
+	if addr.CityName, err = p.city(); err != nil {
+		return nil, err
+	}
+	addr.AddrType = inferAddrType(addr.UnitType)
+	return addr, nil
+}
+
+// addrToken is one word of an address. A comma token is a break between
+// parts of the address, like a comma or a new line.
+type addrToken struct {
+	text  string
+	comma bool
+}
+
+// key is the token in upper case without any periods, which is used to look
+// up the token in the word lists.
+func (t addrToken) key() string {
+	return strings.ToUpper(strings.Trim(t.text, "."))
+}
+
+func tokenizeAddr(raw string) []addrToken {
+	var toks []addrToken
+	breakLine := func() {
+		if len(toks) > 0 && !toks[len(toks)-1].comma {
+			toks = append(toks, addrToken{text: ",", comma: true})
+		}
+	}
+	for _, line := range strings.Split(raw, "\n") {
+		for _, part := range strings.Split(line, ",") {
+			for _, word := range strings.Fields(part) {
+				if strings.HasPrefix(word, "#") && len(word) > 1 {
+					toks = append(toks, addrToken{text: "#"})
+					word = word[1:]
+				}
+				toks = append(toks, addrToken{text: word})
+			}
+			breakLine()
+		}
+	}
+	if len(toks) > 0 && toks[len(toks)-1].comma {
+		toks = toks[:len(toks)-1]
+	}
+	return toks
+}
+
+// addrParser parses the tokens in toks[pos:end]. The street and unit are
+// taken from the front of the tokens and the region and postal code from the
+// back, which leaves the city in the middle.
+type addrParser struct {
+	toks     []addrToken
+	pos, end int
+}
+
+func (p *addrParser) peek(i int) (addrToken, bool) {
+	if i < p.pos || i >= p.end {
+		return addrToken{}, false
+	}
+	return p.toks[i], true
+}
+
+// trimCommas skips commas on both ends of the tokens that are left.
+func (p *addrParser) trimCommas() {
+	for p.pos < p.end && p.toks[p.pos].comma {
+		p.pos++
+	}
+	for p.end > p.pos && p.toks[p.end-1].comma {
+		p.end--
+	}
+}
+
+func (p *addrParser) dropCountry() {
+	p.trimCommas()
+	if t, ok := p.peek(p.end - 1); ok && countryNames[t.key()] {
+		p.end--
+	} else if ok && t.key() == "STATES" {
+		if t, ok = p.peek(p.end - 2); ok && t.key() == "UNITED" {
+			p.end -= 2
+		}
+	}
+	p.trimCommas()
+}
+
+func (p *addrParser) postalCode() (string, error) {
+	t, ok := p.peek(p.end - 1)
+	if !ok {
+		return "", &AddressError{Field: "postal code", Msg: "no zip code or postal code"}
+	}
+	// canadian postal codes are usually written as two words
+	if first, ok := p.peek(p.end - 2); ok && !first.comma {
+		if code, ok := anyPostalCode(first.text + " " + t.text); ok {
+			p.end -= 2
+			return code, nil
+		}
+	}
+	if code, ok := anyPostalCode(t.text); ok {
+		p.end--
+		return code, nil
+	}
+	return "", &AddressError{Field: "postal code", Value: t.text, Msg: "not a zip code or postal code"}
+}
+
+// anyPostalCode formats a postal code from any of the markets.
+func anyPostalCode(code string) (string, bool) {
+	for _, m := range Markets {
+		if c, err := m.PostalCode(code); err == nil {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+func (p *addrParser) region() (string, error) {
+	p.trimCommas()
+	// region names have up to three words, like "district of columbia"
+	for n := 3; n >= 1; n-- {
+		if p.end-n < p.pos {
+			continue
+		}
+		words := make([]string, 0, n)
+		for _, t := range p.toks[p.end-n : p.end] {
+			if t.comma {
+				break
+			}
+			words = append(words, t.key())
+		}
+		if len(words) != n {
+			continue
+		}
+		if code, ok := regionNames[strings.Join(words, " ")]; ok {
+			p.end -= n
+			p.trimCommas()
+			return code, nil
+		}
+	}
+	if n := p.end - 1; n >= p.pos {
+		key := p.toks[n].key()
+		for _, m := range Markets {
+			if m.ValidRegion(key) {
+				p.end--
+				p.trimCommas()
+				return key, nil
+			}
+		}
+		return "", &AddressError{Field: "region", Value: p.toks[n].text, Msg: "not a state or province"}
+	}
+	return "", &AddressError{Field: "region", Msg: "no state or province"}
+}
+
+var houseNumRegex = regexp.MustCompile(`^[0-9]+[A-Z]?(-[0-9]+[A-Z]?)?$`)
+
+// street parses the house number and the street name, which is made of an
+// optional directional, the name, the street type, and another optional
+// directional.
+func (p *addrParser) street(addr *StreetAddr) error {
+	t, ok := p.peek(p.pos)
+	if !ok {
+		return &AddressError{Field: "street", Msg: "no street address"}
+	}
+	if !houseNumRegex.MatchString(t.key()) {
+		return &AddressError{Field: "street number", Value: t.text, Msg: "the address must start with a house number"}
+	}
+	addr.StreetNum = strings.Trim(t.text, ".")
+	p.pos++
+	if t, ok = p.peek(p.pos); ok && t.text == "1/2" {
+		addr.StreetNum += " 1/2"
+		p.pos++
+	}
+
+	var name []string
+	// a directional is only a prefix when there is more street name after it,
+	// "123 North St" is on North Street.
+	if t, ok = p.peek(p.pos); ok && isDirectional(t) {
+		if next, ok := p.peek(p.pos + 1); ok && !next.comma && !isStreetType(next) {
+			name = append(name, directionals[t.key()])
+			p.pos++
+		}
+	}
+
+	stop := p.streetEnd()
+	words := p.toks[p.pos:stop]
+	p.pos = stop
+	var post string
+	if n := len(words); n > 1 && isDirectional(words[n-1]) {
+		post = directionals[words[n-1].key()]
+		words = words[:n-1]
+	} else if p.postDirectional() {
+		post = directionals[p.toks[p.pos].key()]
+		p.pos++
+	}
+	for i, t := range words {
+		// only the last word is a street type, "Court St" is on Court Street
+		if abbr, ok := streetTypes[t.key()]; ok && i == len(words)-1 && i > 0 {
+			name = append(name, abbr)
+		} else {
+			name = append(name, t.text)
+		}
+	}
+	if len(words) == 0 {
+		return &AddressError{Field: "street", Value: addr.StreetNum, Msg: "no street name after the house number"}
+	}
+	if post != "" {
+		name = append(name, post)
+	}
+	addr.StreetName = strings.Join(name, " ")
+	addr.Street = addr.StreetNum + " " + addr.StreetName
+	return nil
+}
+
+// streetEnd finds the index of the token after the last word of the street
+// name. The street ends at the first comma. Without a comma, the street ends
+// at the street type, or before a unit.
+func (p *addrParser) streetEnd() int {
+	i := p.pos
+	for ; i < p.end; i++ {
+		t := p.toks[i]
+		if t.comma || (i > p.pos && isUnitDesignator(t)) {
+			return i
+		}
+	}
+	// no comma or unit, so look for the street type
+	for i = p.pos + 1; i < p.end; i++ {
+		if !isStreetType(p.toks[i]) {
+			continue
+		}
+		// "Court St" has two street types in a row
+		for i+1 < p.end && isStreetType(p.toks[i+1]) {
+			i++
+		}
+		return i + 1
+	}
+	return p.end
+}
+
+// postDirectional tells whether the next token is a directional at the end
+// of the street name. Without a comma, "123 Main St West Palm Beach" is
+// ambiguous so only abbreviations like "NW" or a directional that comes right
+// before a comma or unit are used.
+func (p *addrParser) postDirectional() bool {
+	t, ok := p.peek(p.pos)
+	if !ok || !isDirectional(t) {
+		return false
+	}
+	if len(t.key()) <= 2 {
+		return true
+	}
+	next, ok := p.peek(p.pos + 1)
+	return !ok || next.comma || isUnitDesignator(next)
+}
+
+func (p *addrParser) unit(addr *StreetAddr) error {
+	p.trimCommas()
+	t, ok := p.peek(p.pos)
+	if !ok || !isUnitDesignator(t) {
+		return nil
+	}
+	addr.UnitType = unitTypes[t.key()]
+	p.pos++
+	if t, ok = p.peek(p.pos); ok && t.text == "#" {
+		p.pos++
+	}
+	t, ok = p.peek(p.pos)
+	if !ok || t.comma {
+		return &AddressError{Field: "unit", Value: addr.UnitType, Msg: "no unit number"}
+	}
+	addr.UnitNumber = strings.Trim(t.text, ".")
+	p.pos++
+	return nil
+}
+
+func (p *addrParser) city() (string, error) {
+	p.trimCommas()
+	var words []string
+	for _, t := range p.toks[p.pos:p.end] {
+		if !t.comma {
+			words = append(words, t.text)
+		}
+	}
+	if len(words) == 0 {
+		return "", &AddressError{Field: "city", Msg: "no city"}
+	}
+	return strings.Join(words, " "), nil
+}
+
+// inferAddrType guesses the dominos address type from the type of unit.
+func inferAddrType(unitType string) string {
+	switch unitType {
+	case "Apartment", "Unit":
+		return "Apartment"
+	case "Suite", "Floor", "Room", "Department":
+		return "Business"
+	}
+	return ""
+}
+
+func isDirectional(t addrToken) bool {
+	_, ok := directionals[t.key()]
+	return ok && !t.comma
+}
+
+func isStreetType(t addrToken) bool {
+	_, ok := streetTypes[t.key()]
+	return ok && !t.comma
+}
+
+func isUnitDesignator(t addrToken) bool {
+	_, ok := unitTypes[t.key()]
+	return ok && !t.comma
+}
+
+var directionals = map[string]string{
+	"N": "N", "S": "S", "E": "E", "W": "W",
+	"NE": "NE", "NW": "NW", "SE": "SE", "SW": "SW",
+	"NORTH": "N", "SOUTH": "S", "EAST": "E", "WEST": "W",
+	"NORTHEAST": "NE", "NORTHWEST": "NW", "SOUTHEAST": "SE", "SOUTHWEST": "SW",
+}
+
+// streetTypes maps street types to their usual abbreviation.
+var streetTypes = map[string]string{
+	"ALLEY": "Aly", "ALY": "Aly",
+	"AVENUE": "Ave", "AVE": "Ave", "AV": "Ave",
+	"BOULEVARD": "Blvd", "BLVD": "Blvd",
+	"CIRCLE": "Cir", "CIR": "Cir",
+	"COURT": "Ct", "CT": "Ct",
+	"CRESCENT": "Cres", "CRES": "Cres",
+	"DRIVE": "Dr", "DR": "Dr",
+	"EXPRESSWAY": "Expy", "EXPY": "Expy",
+	"FREEWAY": "Fwy", "FWY": "Fwy",
+	"HIGHWAY": "Hwy", "HWY": "Hwy",
+	"LANE": "Ln", "LN": "Ln",
+	"LOOP":    "Loop",
+	"PARKWAY": "Pkwy", "PKWY": "Pkwy",
+	"PLACE": "Pl", "PL": "Pl",
+	"PLAZA": "Plz", "PLZ": "Plz",
+	"ROAD": "Rd", "RD": "Rd",
+	"SQUARE": "Sq", "SQ": "Sq",
+	"STREET": "St", "ST": "St", "STR": "St",
+	"TERRACE": "Ter", "TER": "Ter",
+	"TRAIL": "Trl", "TRL": "Trl",
+	"WAY": "Way",
+}
+
+// unitTypes maps unit designators to the unit type that is used in a
+// StreetAddr. A "#" has no unit type.
+var unitTypes = map[string]string{
+	"#":   "",
+	"APT": "Apartment", "APARTMENT": "Apartment",
+	"UNIT": "Unit",
+	"STE":  "Suite", "SUITE": "Suite",
+	"FL": "Floor", "FLOOR": "Floor",
+	"RM": "Room", "ROOM": "Room",
+	"BLDG": "Building", "BUILDING": "Building",
+	"DEPT": "Department", "DEPARTMENT": "Department",
+	"LOT":  "Lot",
+	"TRLR": "Trailer", "TRAILER": "Trailer",
+}
+
+var countryNames = map[string]bool{
+	"US": true, "USA": true, "CANADA": true,
+}
+
+// regionNames maps the full names of states and provinces to their codes.
+var regionNames = map[string]string{
+	"ALABAMA": "AL", "ALASKA": "AK", "ARIZONA": "AZ", "ARKANSAS": "AR",
+	"CALIFORNIA": "CA", "COLORADO": "CO", "CONNECTICUT": "CT", "DELAWARE": "DE",
+	"DISTRICT OF COLUMBIA": "DC", "FLORIDA": "FL", "GEORGIA": "GA", "HAWAII": "HI",
+	"IDAHO": "ID", "ILLINOIS": "IL", "INDIANA": "IN", "IOWA": "IA", "KANSAS": "KS",
+	"KENTUCKY": "KY", "LOUISIANA": "LA", "MAINE": "ME", "MARYLAND": "MD",
+	"MASSACHUSETTS": "MA", "MICHIGAN": "MI", "MINNESOTA": "MN", "MISSISSIPPI": "MS",
+	"MISSOURI": "MO", "MONTANA": "MT", "NEBRASKA": "NE", "NEVADA": "NV",
+	"NEW HAMPSHIRE": "NH", "NEW JERSEY": "NJ", "NEW MEXICO": "NM", "NEW YORK": "NY",
+	"NORTH CAROLINA": "NC", "NORTH DAKOTA": "ND", "OHIO": "OH", "OKLAHOMA": "OK",
+	"OREGON": "OR", "PENNSYLVANIA": "PA", "RHODE ISLAND": "RI",
+	"SOUTH CAROLINA": "SC", "SOUTH DAKOTA": "SD", "TENNESSEE": "TN", "TEXAS": "TX",
+	"UTAH": "UT", "VERMONT": "VT", "VIRGINIA": "VA", "WASHINGTON": "WA",
+	"WEST VIRGINIA": "WV", "WISCONSIN": "WI", "WYOMING": "WY", "PUERTO RICO": "PR",
+
+	"ALBERTA": "AB", "BRITISH COLUMBIA": "BC", "MANITOBA": "MB", "NEW BRUNSWICK": "NB",
+	"NEWFOUNDLAND AND LABRADOR": "NL", "NOVA SCOTIA": "NS", "NORTHWEST TERRITORIES": "NT",
+	"NUNAVUT": "NU", "ONTARIO": "ON", "PRINCE EDWARD ISLAND": "PE", "QUEBEC": "QC",
+	"SASKATCHEWAN": "SK", "YUKON": "YT",
+}
@@ -53,6 +53,9 @@ type Payment struct {
 	Expiration string `json:"Expiration"`
 	CardType   string `json:"Type"`
 	CVV        string `json:"SecurityCode"`
+
+	// PostalCode is the card's billing zip code.
+	PostalCode string `json:"PostalCode,omitempty"`
 }
 
 // Num returns the card number as a string.
@@ -83,13 +86,17 @@ func (p *Payment) Code() string {
 var _ Card = (*Payment)(nil)
 
 func makeOrderPaymentFromCard(c Card) *orderPayment {
-	return &orderPayment{
+	p := &orderPayment{
 		Number:       c.Num(),
 		Expiration:   formatDate(c.ExpiresOn()),
 		SecurityCode: c.Code(),
 		Type:         "CreditCard",
 		CardType:     findCardType(c.Num()),
 	}
+	if payment, ok := c.(*Payment); ok {
+		p.PostalCode = payment.PostalCode
+	}
+	return p
 }
 
 func formatDate(t time.Time) string {
@@ -152,7 +159,8 @@ type orderPayment struct {
 
 	// These next fields are just for dominos
 
-	Amount         float64
+	Amount         Money
+	TipAmount      *Money `json:",omitempty"`
 	CardID         string `json:"CardID,omitempty"`
 	ProviderID     string
 	OTP            string
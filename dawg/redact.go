@@ -0,0 +1,66 @@
+package dawg
+
+import (
+	"strings"
+)
+
+// MaskCardNumber hides all but the last four digits of a card number.
+func MaskCardNumber(num string) string {
+	return maskKeepLast(num, 4)
+}
+
+// MaskPhone hides all but the last four digits of a phone number.
+func MaskPhone(phone string) string {
+	return maskKeepLast(phone, 4)
+}
+
+// MaskEmail hides all of an email address's username except for the first
+// character. The domain is left as is.
+func MaskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return maskKeepLast(email, 0)
+	}
+	if at == 0 {
+		return email
+	}
+	return email[:1] + strings.Repeat("*", at-1) + email[at:]
+}
+
+func maskKeepLast(s string, n int) string {
+	if len(s) <= n {
+		return strings.Repeat("*", len(s))
+	}
+	return strings.Repeat("*", len(s)-n) + s[len(s)-n:]
+}
+
+const (
+	redactedExpiration = "****"
+	redactedCode       = "***"
+)
+
+// Redacted returns the order as json with the payment card details
+// hidden and the customer's phone and email partially hidden. It is meant to
+// be used when logging orders.
+func (o *Order) Redacted() string {
+	cp := *o
+	cp.Phone = MaskPhone(o.Phone)
+	cp.Email = MaskEmail(o.Email)
+	cp.Payments = make([]*orderPayment, len(o.Payments))
+	for i, p := range o.Payments {
+		redacted := *p
+		redacted.Number = MaskCardNumber(p.Number)
+		if p.Expiration != "" {
+			redacted.Expiration = redactedExpiration
+		}
+		if p.SecurityCode != "" {
+			redacted.SecurityCode = redactedCode
+		}
+		cp.Payments[i] = &redacted
+	}
+	raw := cp.raw()
+	if raw == nil {
+		return "{\"error\":\"could not encode order\"}"
+	}
+	return strings.TrimSpace(raw.String())
+}
@@ -0,0 +1,40 @@
+package dawg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestMask(t *testing.T) {
+	tests.InitHelpers(t)
+	tests.StrEq(MaskCardNumber("4111111111111111"), "************1111", "bad card mask")
+	tests.StrEq(MaskCardNumber("123"), "***", "short numbers should be fully masked")
+	tests.StrEq(MaskPhone("2025550123"), "******0123", "bad phone mask")
+	tests.StrEq(MaskEmail("bob@example.com"), "b**@example.com", "bad email mask")
+	tests.StrEq(MaskEmail("not-an-email"), "************", "bad email mask")
+	tests.StrEq(MaskEmail(""), "", "empty email should stay empty")
+}
+
+func TestOrder_Redacted(t *testing.T) {
+	o := &Order{
+		Phone: "2025550123",
+		Email: "bob@example.com",
+	}
+	o.AddCard(NewCard("4111111111111111", "01/30", 123))
+	s := o.Redacted()
+	for _, secret := range []string{"4111111111111111", "0130", "123", "2025550123", "bob@example.com"} {
+		if strings.Contains(s, "\""+secret+"\"") {
+			t.Errorf("redacted order should not contain %s:\n%s", secret, s)
+		}
+	}
+	for _, masked := range []string{"************1111", "******0123", "b**@example.com"} {
+		if !strings.Contains(s, masked) {
+			t.Errorf("redacted order should contain %s", masked)
+		}
+	}
+	if o.Payments[0].Number != "4111111111111111" || o.Phone != "2025550123" {
+		t.Error("Redacted should not change the order")
+	}
+}
@@ -0,0 +1,291 @@
+package dawg
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SearchOptions changes how Menu.Search matches items.
+type SearchOptions struct {
+	// Limit is the most results that will be returned. Zero means there is
+	// no limit.
+	Limit int
+
+	// Exact turns off typo tolerance so that every word in the query has to
+	// match the beginning of a word on the item.
+	Exact bool
+
+	// NoVariants leaves variants (the sized versions of a product
+	// like '12SCREEN') out of the results.
+	NoVariants bool
+}
+
+// SearchResult is one item found by Menu.Search.
+type SearchResult struct {
+	Item Item
+
+	// Score is how well the item matched the query. Higher is better.
+	Score float64
+
+	// Category is the name of the menu category that the item is listed
+	// under.
+	Category string
+}
+
+// how much a match counts depending on where it was found
+const (
+	nameWeight = 3.0
+	tagWeight  = 1.5
+	descWeight = 1.0
+)
+
+// Search looks for menu items with a name, description, or tags that match
+// the query. Every word in the query has to match a word on the item but
+// small typos are allowed unless opts.Exact is set. The results are sorted
+// with the best match first.
+func (m *Menu) Search(query string, opts SearchOptions) []SearchResult {
+	words := searchTokens(query)
+	if len(words) == 0 {
+		return nil
+	}
+	idx := m.Index()
+	code := strings.ToUpper(strings.TrimSpace(query))
+
+	var results []SearchResult
+	for _, c := range idx.candidates(words, code, opts.Exact) {
+		var (
+			itm  Item
+			desc string
+			prod string
+			tags map[string]interface{}
+		)
+		// codes that are in the index but not the menu are skipped in case
+		// the index is out of date
+		switch idx.Kinds[c] {
+		case ProductItem:
+			p, ok := m.Products[c]
+			if !ok {
+				continue
+			}
+			itm, desc, prod, tags = p, p.Description, p.Code, p.Tags
+		case PreconfiguredItem:
+			pc, ok := m.Preconfigured[c]
+			if !ok {
+				continue
+			}
+			itm, desc, prod, tags = pc, pc.Description, pc.Code, pc.Tags
+		case VariantItem:
+			v, ok := m.Variants[c]
+			if opts.NoVariants || !ok {
+				continue
+			}
+			v = m.initVariant(v)
+			if p := v.GetProduct(); p != nil {
+				desc = p.Description
+			}
+			itm, prod, tags = v, v.ProductCode, v.Tags
+		default:
+			continue
+		}
+
+		score, ok := matchItem(words, itm.ItemName(), desc, tags, opts.Exact)
+		if strings.ToUpper(c) == code {
+			score, ok = score+10*nameWeight, true
+		}
+		if !ok {
+			continue
+		}
+		cat := idx.CategoryName(c)
+		if cat == "" {
+			cat = idx.CategoryName(prod)
+		}
+		results = append(results, SearchResult{Item: itm, Score: score, Category: cat})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		// shorter names are closer to the query
+		if la, lb := len(a.Item.ItemName()), len(b.Item.ItemName()); la != lb {
+			return la < lb
+		}
+		if a.Item.ItemName() != b.Item.ItemName() {
+			return a.Item.ItemName() < b.Item.ItemName()
+		}
+		return a.Item.ItemCode() < b.Item.ItemCode()
+	})
+	if opts.Limit > 0 && len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+	return results
+}
+
+// candidates uses the index to find the codes of items that might match a
+// search. Each word in the query has to match at least one word on an item.
+func (idx *MenuIndex) candidates(query []string, code string, exact bool) []string {
+	var set map[string]bool
+	for _, q := range query {
+		found := make(map[string]bool)
+		for word, codes := range idx.Tokens {
+			if wordScore(q, []string{word}, exact) == 0 {
+				continue
+			}
+			for _, c := range codes {
+				if set == nil || set[c] {
+					found[c] = true
+				}
+			}
+		}
+		set = found
+		if len(set) == 0 {
+			break
+		}
+	}
+	for c := range idx.Kinds {
+		if strings.ToUpper(c) == code {
+			set[c] = true
+		}
+	}
+	return sortedKeys(set)
+}
+
+// matchItem scores an item for a query. The second return value is false if
+// any word in the query was not found.
+func matchItem(query []string, name, desc string, tags map[string]interface{}, exact bool) (float64, bool) {
+	nameWords := searchTokens(name)
+	descWords := searchTokens(desc)
+	tagWords := tagTokens(tags)
+
+	var total float64
+	for _, q := range query {
+		best := nameWeight * wordScore(q, nameWords, exact)
+		if s := tagWeight * wordScore(q, tagWords, exact); s > best {
+			best = s
+		}
+		if s := descWeight * wordScore(q, descWords, exact); s > best {
+			best = s
+		}
+		if best == 0 {
+			return 0, false
+		}
+		total += best
+	}
+	// reward items where the whole query shows up in the name
+	if len(query) > 1 && strings.Contains(strings.Join(nameWords, " "), strings.Join(query, " ")) {
+		total += nameWeight
+	}
+	return total, true
+}
+
+// wordScore gives the best score for a query word against a list of words. A
+// whole word match is worth 1 and prefixes and typos are worth less.
+func wordScore(q string, words []string, exact bool) float64 {
+	var best float64
+	for _, w := range words {
+		var s float64
+		switch {
+		case w == q:
+			s = 1
+		case len(q) > 1 && strings.HasPrefix(w, q):
+			s = 0.8
+		case !exact:
+			n := []rune(q)
+			if d := editDistance(n, []rune(w), typoLimit(len(n))); d >= 0 && d <= typoLimit(len(n)) {
+				s = 0.7 - 0.2*float64(d-1)
+			}
+		}
+		if s > best {
+			best = s
+			if best == 1 {
+				break
+			}
+		}
+	}
+	return best
+}
+
+// typoLimit is the number of typos allowed in a word of length n.
+func typoLimit(n int) int {
+	switch {
+	case n <= 3:
+		return 0
+	case n <= 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance is the levenshtein distance between a and b. If the distance
+// is more than max then -1 is returned.
+func editDistance(a, b []rune, max int) int {
+	if diff := len(a) - len(b); diff > max || -diff > max {
+		return -1
+	}
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if curr[j] < rowMin {
+				rowMin = curr[j]
+			}
+		}
+		if rowMin > max {
+			return -1
+		}
+		prev, curr = curr, prev
+	}
+	if prev[len(b)] > max {
+		return -1
+	}
+	return prev[len(b)]
+}
+
+func minInt(nums ...int) int {
+	min := nums[0]
+	for _, n := range nums[1:] {
+		if n < min {
+			min = n
+		}
+	}
+	return min
+}
+
+// searchTokens splits a string into lowercase words.
+func searchTokens(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// tagTokens gets the searchable words out of an item's tags. String values
+// are split into words and tags set to true add their own name (so an item
+// tagged with "Vegetarian": true will match "vegetarian").
+func tagTokens(tags map[string]interface{}) (words []string) {
+	for k, v := range tags {
+		switch val := v.(type) {
+		case bool:
+			if val {
+				words = append(words, searchTokens(k)...)
+			}
+		case string:
+			if !strings.ContainsAny(val, "=,") { // skip topping lists
+				words = append(words, searchTokens(val)...)
+			}
+		}
+	}
+	return words
+}
@@ -0,0 +1,113 @@
+package dawg
+
+import (
+	"testing"
+)
+
+func testSearchMenu() *Menu {
+	m := &Menu{
+		Products: map[string]*Product{
+			"S_PIZZA": {
+				ItemCommon:  ItemCommon{Code: "S_PIZZA", Name: "Hand Tossed"},
+				Description: "Garlic-seasoned crust with a rich, buttery taste.",
+				Variants:    []string{"10SCREEN", "12SCREEN"},
+				ProductType: "Pizza",
+			},
+			"S_PEPPER": {
+				ItemCommon:  ItemCommon{Code: "S_PEPPER", Name: "Pepperoni Feast"},
+				Description: "Extra pepperoni and cheese.",
+				Variants:    []string{"14SCPEPPER"},
+				ProductType: "Pizza",
+			},
+			"S_BREAD": {
+				ItemCommon: ItemCommon{
+					Code: "S_BREAD", Name: "Stuffed Cheesy Bread",
+					Tags: map[string]interface{}{"Vegetarian": true, "DefaultToppings": "Cp=1"},
+				},
+				Description: "Oven-baked bread stuffed with cheese.",
+				Variants:    []string{"B8PCSCB"},
+				ProductType: "Bread",
+			},
+		},
+		Variants: map[string]*Variant{
+			"10SCREEN":   {ItemCommon: ItemCommon{Code: "10SCREEN", Name: "Small (10\") Hand Tossed Pizza"}, Price: Cents(799), ProductCode: "S_PIZZA"},
+			"12SCREEN":   {ItemCommon: ItemCommon{Code: "12SCREEN", Name: "Medium (12\") Hand Tossed Pizza"}, Price: Cents(999), ProductCode: "S_PIZZA"},
+			"14SCPEPPER": {ItemCommon: ItemCommon{Code: "14SCPEPPER", Name: "Large (14\") Pepperoni Feast"}, Price: Cents(1599), ProductCode: "S_PEPPER"},
+			"B8PCSCB":    {ItemCommon: ItemCommon{Code: "B8PCSCB", Name: "Stuffed Cheesy Bread"}, Price: Cents(699), ProductCode: "S_BREAD"},
+		},
+	}
+	m.Categorization.Food = MenuCategory{
+		Categories: []MenuCategory{
+			{Name: "Pizza", Code: "BuildYourOwn", Products: []string{"S_PIZZA"}},
+			{Name: "Specialty Pizza", Code: "Specialty", Products: []string{"S_PEPPER"}},
+			{Name: "Bread", Code: "Bread", Products: []string{"S_BREAD"}},
+		},
+	}
+	return m
+}
+
+func TestMenuSearch(t *testing.T) {
+	m := testSearchMenu()
+	tt := []struct {
+		query string
+		opts  SearchOptions
+		first string
+		n     int
+	}{
+		{query: "pepperoni", first: "S_PEPPER", n: 2},
+		{query: "peperoni", first: "S_PEPPER", n: 2},  // typo
+		{query: "pepperoin", first: "S_PEPPER", n: 2}, // swapped letters
+		{query: "peperoni", opts: SearchOptions{Exact: true}, n: 0},
+		{query: "medium hand tossed", first: "12SCREEN", n: 1},
+		{query: "hand tossed", first: "S_PIZZA", n: 3},
+		{query: "hand tossed", opts: SearchOptions{Limit: 2}, first: "S_PIZZA", n: 2},
+		{query: "hand tossed", opts: SearchOptions{NoVariants: true}, first: "S_PIZZA", n: 1},
+		{query: "12screen", first: "12SCREEN", n: 1},
+		{query: "buttery", first: "S_PIZZA", n: 3}, // variants get the description from the product
+		{query: "vegetarian", first: "S_BREAD", n: 1},
+		{query: "pepperoni wings", n: 0},
+		{query: "  ", n: 0},
+	}
+	for _, tc := range tt {
+		res := m.Search(tc.query, tc.opts)
+		if len(res) != tc.n {
+			t.Errorf("Search(%q): got %d results, want %d", tc.query, len(res), tc.n)
+			continue
+		}
+		if tc.n > 0 && res[0].Item.ItemCode() != tc.first {
+			t.Errorf("Search(%q): first result is %s, want %s", tc.query, res[0].Item.ItemCode(), tc.first)
+		}
+	}
+
+	res := m.Search("medium", SearchOptions{})
+	if len(res) != 1 {
+		t.Fatalf("got %d results", len(res))
+	}
+	if res[0].Category != "Pizza" {
+		t.Errorf("variant should be in its product's category; got %q", res[0].Category)
+	}
+	if _, ok := res[0].Item.(*Variant); !ok {
+		t.Errorf("expected a variant; got %T", res[0].Item)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tt := []struct {
+		a, b string
+		max  int
+		want int
+	}{
+		{"pepperoni", "pepperoni", 2, 0},
+		{"peperoni", "pepperoni", 2, 1},
+		{"pepporoin", "pepperoni", 2, -1},
+		{"chese", "cheese", 1, 1},
+		{"wings", "wing", 1, 1},
+		{"wings", "things", 1, -1},
+		{"", "abc", 3, 3},
+	}
+	for _, tc := range tt {
+		if d := editDistance([]rune(tc.a), []rune(tc.b), tc.max); d != tc.want {
+			t.Errorf("editDistance(%q, %q, %d) = %d, want %d", tc.a, tc.b, tc.max, d, tc.want)
+		}
+	}
+}
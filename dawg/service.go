@@ -0,0 +1,124 @@
+package dawg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ServiceMethod is the way that an order gets from the store to the customer.
+type ServiceMethod string
+
+const (
+	// Delivery is a dominos service method that will result
+	// in a pizza delivery.
+	Delivery ServiceMethod = "Delivery"
+
+	// Carryout is a dominos service method that
+	// will require users to go and pickup their pizza.
+	Carryout ServiceMethod = "Carryout"
+
+	// DriveUpCarryout is carryout where the order is brought out to the
+	// customer's car, also known as curbside pickup. Orders for drive-up
+	// carryout need a Vehicle.
+	DriveUpCarryout ServiceMethod = "DriveUpCarryout"
+
+	// DineIn is carryout that is eaten at the store.
+	DineIn ServiceMethod = "DineIn"
+)
+
+// ServiceMethods is a list of all the service methods.
+var ServiceMethods = []ServiceMethod{Delivery, Carryout, DriveUpCarryout, DineIn}
+
+var serviceAliases = map[string]ServiceMethod{
+	"delivery":        Delivery,
+	"carryout":        Carryout,
+	"pickup":          Carryout,
+	"driveup":         DriveUpCarryout,
+	"driveupcarryout": DriveUpCarryout,
+	"curbside":        DriveUpCarryout,
+	"dinein":          DineIn,
+}
+
+// ParseServiceMethod finds the service method for a name. Names are not case
+// sensitive and some other common names like "curbside" or "dine-in" are
+// allowed.
+func ParseServiceMethod(name string) (ServiceMethod, error) {
+	key := strings.ToLower(strings.NewReplacer("-", "", "_", "", " ", "").Replace(name))
+	if m, ok := serviceAliases[key]; ok {
+		return m, nil
+	}
+	return "", ErrBadService
+}
+
+// Valid returns ErrBadService if the service method is not a known service
+// method.
+func (m ServiceMethod) Valid() error {
+	for _, method := range ServiceMethods {
+		if m == method {
+			return nil
+		}
+	}
+	return ErrBadService
+}
+
+// IsCarryout tells whether the customer picks up the order at the store,
+// which is true for every service method but Delivery.
+func (m ServiceMethod) IsCarryout() bool {
+	return m != Delivery
+}
+
+// NeedsVehicle tells whether an order with the service method needs to have
+// a Vehicle.
+func (m ServiceMethod) NeedsVehicle() bool {
+	return m == DriveUpCarryout
+}
+
+// locatorType is the type of service given to the dominos store locator,
+// which only knows about delivery and carryout.
+func (m ServiceMethod) locatorType() ServiceMethod {
+	if m.IsCarryout() {
+		return Carryout
+	}
+	return Delivery
+}
+
+func (m ServiceMethod) String() string {
+	return string(m)
+}
+
+// Vehicle is the customer's car for drive-up carryout orders so that the
+// store knows who to bring the order out to.
+type Vehicle struct {
+	Make  string `json:"Make"`
+	Color string `json:"Color"`
+}
+
+// Supports returns an error if the store does not offer a service method.
+// Every store offers delivery and carryout. Other service methods have to be
+// listed in the store's ServiceIsOpen.
+func (s *Store) Supports(m ServiceMethod) error {
+	if err := m.Valid(); err != nil {
+		return err
+	}
+	if m == Delivery || m == Carryout {
+		return nil
+	}
+	if _, ok := s.ServiceIsOpen[string(m)]; !ok {
+		return fmt.Errorf("store %s does not offer %s", s.ID, m)
+	}
+	return nil
+}
+
+// checkService makes sure that the order has the extra fields that its
+// service method needs.
+func (o *Order) checkService() error {
+	if err := o.ServiceMethod.Valid(); err != nil {
+		return err
+	}
+	if o.ServiceMethod.NeedsVehicle() &&
+		(o.Vehicle == nil || o.Vehicle.Make == "" || o.Vehicle.Color == "") {
+		return errors.New("drive-up carryout orders need the make and color of the car")
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package dawg
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestParseServiceMethod(t *testing.T) {
+	for name, exp := range map[string]ServiceMethod{
+		"Delivery":        Delivery,
+		"carryout":        Carryout,
+		"pickup":          Carryout,
+		"curbside":        DriveUpCarryout,
+		"drive-up":        DriveUpCarryout,
+		"DriveUpCarryout": DriveUpCarryout,
+		"dine in":         DineIn,
+		"Dine_In":         DineIn,
+	} {
+		m, err := ParseServiceMethod(name)
+		if err != nil {
+			t.Errorf("could not parse %q: %v", name, err)
+		}
+		if m != exp {
+			t.Errorf("%q: got %s, want %s", name, m, exp)
+		}
+	}
+	if _, err := ParseServiceMethod("teleport"); err != ErrBadService {
+		t.Error("expected ErrBadService for an unknown service method")
+	}
+	if ServiceMethod("delivery").Valid() == nil {
+		t.Error("service methods are case sensitive")
+	}
+	if DineIn.locatorType() != Carryout || Delivery.locatorType() != Delivery {
+		t.Error("wrong store locator type")
+	}
+}
+
+func TestStore_Supports(t *testing.T) {
+	s := &Store{ID: "4336", ServiceIsOpen: map[string]bool{"Delivery": true, "Carryout": true, "DriveUpCarryout": false}}
+	for _, m := range []ServiceMethod{Delivery, Carryout, DriveUpCarryout} {
+		if err := s.Supports(m); err != nil {
+			t.Errorf("store should support %s: %v", m, err)
+		}
+	}
+	if s.Supports(DineIn) == nil {
+		t.Error("store does not list dine-in")
+	}
+	if s.Supports("Teleport") != ErrBadService {
+		t.Error("expected ErrBadService")
+	}
+}
+
+func TestOrder_checkService(t *testing.T) {
+	o := &Order{ServiceMethod: DriveUpCarryout}
+	if o.checkService() == nil {
+		t.Error("drive-up carryout needs a vehicle")
+	}
+	o.Vehicle = &Vehicle{Make: "Honda"}
+	if o.checkService() == nil {
+		t.Error("drive-up carryout needs the color of the vehicle")
+	}
+	o.Vehicle.Color = "blue"
+	if err := o.checkService(); err != nil {
+		t.Error(err)
+	}
+	o = &Order{ServiceMethod: "Teleport"}
+	if o.PlaceOrder() != ErrBadService {
+		t.Error("orders with a bad service method should not be sent")
+	}
+}
+
+func TestFindNearbyStores_ServiceType(t *testing.T) {
+	var locatorType string
+	c := &client{host: orderHost, Client: &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		locatorType = r.URL.Query().Get("type")
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{"Stores":[]}`)),
+			Header:     make(http.Header),
+			Request:    r,
+		}, nil
+	})}}
+	if _, err := findNearbyStores(c, testAddress(), DineIn); err != nil {
+		t.Fatal(err)
+	}
+	if locatorType != "Carryout" {
+		t.Errorf("the store locator should get carryout for dine-in, got %q", locatorType)
+	}
+}
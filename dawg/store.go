@@ -10,14 +10,6 @@ import (
 )
 
 const (
-	// Delivery is a dominos service method that will result
-	// in a pizza delivery.
-	Delivery = "Delivery"
-
-	// Carryout is a dominos service method that
-	// will require users to go and pickup their pizza.
-	Carryout = "Carryout"
-
 	profileEndpoint = "/power/store/%s/profile"
 
 	// DefaultLang is the package language variable
@@ -29,15 +21,15 @@ const (
 // NearestStore gets the dominos location closest to the given address.
 //
 // The addr argument should be the address to deliver to not the address of the
-// store itself. The service should be either "Carryout" or "Delivery", this will
-// determine wether the final order will be for pickup or delivery.
-func NearestStore(addr Address, service string) (*Store, error) {
+// store itself. The service will determine wether the final order will be
+// for pickup or delivery.
+func NearestStore(addr Address, service ServiceMethod) (*Store, error) {
 	return getNearestStore(orderClient, addr, service)
 }
 
 // GetNearbyStores is a way of getting all the nearby stores
 // except they will by full initialized.
-func GetNearbyStores(addr Address, service string) ([]*Store, error) {
+func GetNearbyStores(addr Address, service ServiceMethod) ([]*Store, error) {
 	return asyncNearbyStores(orderClient, addr, service)
 }
 
@@ -49,7 +41,7 @@ func GetNearbyStores(addr Address, service string) ([]*Store, error) {
 //
 // The addr argument should be the address to deliver to not the address of the
 // store itself.
-func NewStore(id string, service string, addr Address) (*Store, error) {
+func NewStore(id string, service ServiceMethod, addr Address) (*Store, error) {
 	store := &Store{userService: service, userAddress: addr, cli: orderClient}
 	return store, InitStore(id, store)
 }
@@ -159,7 +151,7 @@ type Store struct {
 	Status int
 
 	userAddress Address
-	userService string
+	userService ServiceMethod
 	menu        *Menu
 	cli         *client
 }
@@ -185,13 +177,13 @@ func (s *Store) Menu() (*Menu, error) {
 // NewOrder is a convenience function for creating an order from some of the store variables.
 func (s *Store) NewOrder() *Order {
 	return &Order{
-		LanguageCode:  DefaultLang,
+		LanguageCode:  s.Market().Lang,
 		ServiceMethod: s.userService,
 		StoreID:       s.ID,
 		Products:      []*OrderProduct{},
 		Address:       StreetAddrFromAddress(s.userAddress),
 		Payments:      []*orderPayment{},
-		cli:           orderClient,
+		cli:           s.Market().client(),
 	}
 }
 
@@ -201,13 +193,13 @@ func (s *Store) MakeOrder(firstname, lastname, email string) *Order {
 		FirstName:     firstname,
 		LastName:      lastname,
 		Email:         email,
-		LanguageCode:  DefaultLang,
+		LanguageCode:  s.Market().Lang,
 		ServiceMethod: s.userService,
 		StoreID:       s.ID,
 		Products:      []*OrderProduct{},
 		Address:       StreetAddrFromAddress(s.userAddress),
 		Payments:      []*orderPayment{},
-		cli:           orderClient,
+		cli:           s.Market().client(),
 	}
 }
 
@@ -242,7 +234,7 @@ func (s *Store) FindItem(code string) (Item, error) {
 // minimum estimated wait time for that store.
 func (s *Store) WaitTime() (min int, max int) {
 	m := s.ServiceEstimatedWait
-	return m[s.userService].Min, m[s.userService].Max
+	return m[string(s.userService)].Min, m[string(s.userService)].Max
 }
 
 type storeLocs struct {
@@ -251,7 +243,7 @@ type storeLocs struct {
 	Stores      []*Store    `json:"Stores"`
 }
 
-func getNearestStore(c *client, addr Address, service string) (*Store, error) {
+func getNearestStore(c *client, addr Address, service ServiceMethod) (*Store, error) {
 	if addr == nil {
 		return nil, errors.New("no address")
 	}
@@ -271,17 +263,16 @@ func getNearestStore(c *client, addr Address, service string) (*Store, error) {
 	return store, initStore(c, store.ID, store)
 }
 
-func findNearbyStores(c *client, addr Address, service string) (*storeLocs, error) {
-	if !(service == Delivery || service == Carryout) {
-		// panic("service must be either 'Delivery' or 'Carryout'")
-		return nil, ErrBadService
+func findNearbyStores(c *client, addr Address, service ServiceMethod) (*storeLocs, error) {
+	if err := service.Valid(); err != nil {
+		return nil, err
 	}
 	// TODO: on the dominos website, the c param can sometimes be just the zip code
 	// and it still works.
 	b, err := c.get("/power/store-locator", &Params{
 		"s":    addr.LineOne(),
 		"c":    format("%s, %s %s", addr.City(), addr.StateCode(), addr.Zip()),
-		"type": service,
+		"type": string(service.locatorType()),
 	})
 	if err != nil {
 		return nil, err
@@ -294,7 +285,7 @@ func findNearbyStores(c *client, addr Address, service string) (*storeLocs, erro
 	return locs, dominosErr(b)
 }
 
-func asyncNearbyStores(cli *client, addr Address, service string) ([]*Store, error) {
+func asyncNearbyStores(cli *client, addr Address, service ServiceMethod) ([]*Store, error) {
 	all, err := findNearbyStores(cli, addr, service)
 	if err != nil {
 		return nil, fmt.Errorf("findNearbyStores: %v", err)
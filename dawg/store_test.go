@@ -68,7 +68,7 @@ func TestGetAllNearbyStores(t *testing.T) {
 		}
 		tests.StrEq(s.ID, validation.Stores[i].ID, "ids are not the same %s %s", stores[i].ID, validation.Stores[i].ID)
 		tests.StrEq(s.Phone, validation.Stores[i].Phone, "wrong phone")
-		tests.StrEq(s.userService, Delivery, "wrong service method")
+		tests.StrEq(string(s.userService), string(Delivery), "wrong service method")
 		tests.StrEq(s.userAddress.City(), addr.City(), "wrong city")
 		tests.StrEq(s.userAddress.LineOne(), addr.LineOne(), "wrong line one")
 		tests.StrEq(s.userAddress.StateCode(), addr.StateCode(), "wrong state code")
@@ -125,7 +125,7 @@ func TestInitStore_Err(t *testing.T) {
 
 func TestGetNearestStore(t *testing.T) {
 	a := testAddress()
-	for _, service := range []string{Delivery, Carryout} {
+	for _, service := range []ServiceMethod{Delivery, Carryout} {
 		s, err := getNearestStore(orderClient, a, service)
 		if err != nil {
 			t.Error(err)
@@ -0,0 +1,117 @@
+package dawg
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	trackerHost     = "tracker.dominos.com"
+	trackerEndpoint = "/tracker-presentation-service/v2/orders"
+)
+
+var trackerClient = &client{
+	host: trackerHost,
+	Client: &http.Client{
+		Timeout:       60 * time.Second,
+		CheckRedirect: noRedirects,
+		Transport: newRoundTripper(func(req *http.Request) error {
+			setDawgUserAgent(req.Header)
+			req.Header.Set("Accept", "application/json")
+			return nil
+		}),
+	},
+}
+
+// TrackedOrder is an order as seen by the dominos order tracker.
+type TrackedOrder struct {
+	StoreID          string
+	OrderID          string
+	Phone            string
+	ServiceMethod    string
+	OrderDescription string
+
+	// OrderStatus is the current stage of the order, like "Prep", "Bake",
+	// "Quality Check", "Out the Door", or "Complete".
+	OrderStatus string
+	// StartTime is the time that the store received the order.
+	StartTime string
+}
+
+// TrackOrders returns the orders that the dominos order tracker has for a
+// phone number in the UnitedStates. The tracker only keeps orders placed in
+// the last few hours.
+func TrackOrders(phone string) ([]*TrackedOrder, error) {
+	return UnitedStates.TrackOrders(phone)
+}
+
+// TrackOrders returns the orders that the dominos order tracker has for a
+// phone number in the market (see TrackOrders).
+func (m *Market) TrackOrders(phone string) ([]*TrackedOrder, error) {
+	phone = strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, phone)
+	if phone == "" {
+		return nil, errors.New("a phone number is needed to track orders")
+	}
+	req := &http.Request{
+		Method: "GET",
+		Host:   trackerClient.host,
+		Proto:  "HTTP/1.1",
+		Header: make(http.Header),
+		URL: &url.URL{
+			Scheme:   "https",
+			Host:     trackerClient.host,
+			Path:     trackerEndpoint,
+			RawQuery: Params{"phonenumber": phone}.Encode(),
+		},
+	}
+	// the tracker is shared by every market
+	req.Header.Set("DPZ-Language", m.Lang)
+	req.Header.Set("DPZ-Market", m.trackerName)
+	b, err := trackerClient.do(req)
+	if err != nil {
+		return nil, err
+	}
+	orders := make([]*TrackedOrder, 0)
+	return orders, json.Unmarshal(b, &orders)
+}
+
+// OrderReceived checks the dominos order tracker to see if an order was
+// actually received by a store in the UnitedStates. This is useful when
+// placing an order fails without a response, like on a timeout.
+func OrderReceived(phone, storeID, orderID string) (bool, error) {
+	return UnitedStates.OrderReceived(phone, storeID, orderID)
+}
+
+// OrderReceived checks the dominos order tracker to see if an order was
+// received by a store in the market (see OrderReceived).
+func (m *Market) OrderReceived(phone, storeID, orderID string) (bool, error) {
+	if orderID == "" {
+		return false, errors.New("no order id")
+	}
+	orders, err := m.TrackOrders(phone)
+	if err != nil {
+		return false, err
+	}
+	for _, o := range orders {
+		if o.OrderID == orderID && (storeID == "" || o.StoreID == storeID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Received checks the dominos order tracker to see if the order was received
+// by the store (see OrderReceived).
+func (o *Order) Received() (bool, error) {
+	return o.Market().OrderReceived(o.Phone, o.StoreID, o.OrderID)
+}
@@ -0,0 +1,64 @@
+package dawg
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestOrderReceived(t *testing.T) {
+	tests.InitHelpers(t)
+	copyclient := trackerClient
+	defer func() { trackerClient = copyclient }()
+
+	var phone, market, lang string
+	trackerClient = &client{host: trackerHost, Client: &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			tests.StrEq(r.URL.Path, trackerEndpoint, "wrong tracker path")
+			phone = r.URL.Query().Get("phonenumber")
+			market, lang = r.Header.Get("DPZ-Market"), r.Header.Get("DPZ-Language")
+			body := `[{"StoreID":"4336","OrderID":"abc123","OrderStatus":"Bake"}]`
+			if phone != "2025550123" {
+				body = `[]`
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+			}, nil
+		}),
+	}}
+
+	o := &Order{Phone: "(202) 555-0123", StoreID: "4336", OrderID: "abc123"}
+	ok, err := o.Received()
+	tests.Check(err)
+	tests.StrEq(phone, "2025550123", "phone number should only have digits")
+	if !ok {
+		t.Error("order should have been received")
+	}
+	tests.StrEq(market, "UNITED_STATES", "wrong tracker market")
+	tests.StrEq(lang, "en", "wrong tracker language")
+
+	o.SetMarket(Canada)
+	_, err = o.Received()
+	tests.Check(err)
+	tests.StrEq(market, "CANADA", "canadian orders should be tracked in the canadian market")
+	tests.StrEq(lang, Canada.Lang, "wrong tracker language")
+
+	ok, err = OrderReceived("2025550123", "1111", "abc123")
+	tests.Check(err)
+	if ok {
+		t.Error("order was sent to a different store")
+	}
+	ok, err = OrderReceived("2025559999", "", "abc123")
+	tests.Check(err)
+	if ok {
+		t.Error("tracker has no orders for this phone")
+	}
+	_, err = OrderReceived("", "4336", "abc123")
+	tests.Exp(err)
+	_, err = OrderReceived("2025550123", "4336", "")
+	tests.Exp(err)
+}
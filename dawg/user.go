@@ -1,8 +1,12 @@
 package dawg
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -12,15 +16,9 @@ import (
 
 // SignIn will create a new UserProfile and sign in the account.
 func SignIn(username, password string) (*UserProfile, error) {
-	a, err := newauth(username, password)
-	if err != nil {
-		return nil, err
-	}
-	return a.login()
+	return UnitedStates.SignIn(username, password)
 }
 
-// TODO: find out how to update a profile on domino's end
-
 // UserProfile is a Dominos user profile.
 type UserProfile struct {
 	FirstName string
@@ -58,8 +56,8 @@ type UserProfile struct {
 	// UpdateTime shows the last time the user's profile was updated
 	UpdateTime string
 
-	// ServiceMethod should be "Delivery" or "Carryout"
-	ServiceMethod string `json:"-"` // this is a package specific field (not from the api)
+	// ServiceMethod is the user's default service method (see SetServiceMethod)
+	ServiceMethod ServiceMethod `json:"-"` // this is a package specific field (not from the api)
 	ordersMeta    *customerOrders
 
 	auth        *auth
@@ -67,11 +65,37 @@ type UserProfile struct {
 	loyaltyData *CustomerLoyalty
 }
 
-// AddAddress will add an address to the dominos account.
-func (u *UserProfile) AddAddress(a Address) {
-	// TODO: consider sending a request to dominos to update the user with this address.
-	// this can be done in a separate go-routine
-	u.Addresses = append(u.Addresses, UserAddressFromAddress(a))
+// AddAddress will add an address to the user's profile. The address is not
+// saved on the dominos account until Update is called.
+func (u *UserProfile) AddAddress(a Address) *UserAddress {
+	addr := UserAddressFromAddress(a)
+	u.Addresses = append(u.Addresses, addr)
+	return addr
+}
+
+// SetDefaultAddress will make the address with the given name the default
+// address of the profile. Like AddAddress, the change is not sent to dominos
+// until Update is called.
+func (u *UserProfile) SetDefaultAddress(name string) error {
+	var found bool
+	for _, a := range u.Addresses {
+		a.IsDefault = a.Name == name
+		found = found || a.IsDefault
+	}
+	if !found {
+		return fmt.Errorf("no address named '%s'", name)
+	}
+	return nil
+}
+
+// Update will send the profile to dominos, saving any changes made to the
+// user's name, phone, email and sms opt-in settings, and addresses.
+func (u *UserProfile) Update() error {
+	body, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+	return u.customerRequest("POST", "", nil, bytes.NewReader(body), u)
 }
 
 var (
@@ -91,7 +115,7 @@ func (u *UserProfile) StoresNearMe() ([]*Store, error) {
 }
 
 // NearestStore will find the the store that is closest to the user's default address.
-func (u *UserProfile) NearestStore(service string) (*Store, error) {
+func (u *UserProfile) NearestStore(service ServiceMethod) (*Store, error) {
 	var err error
 	if u.store != nil {
 		return u.store, nil
@@ -100,7 +124,7 @@ func (u *UserProfile) NearestStore(service string) (*Store, error) {
 	// Pass the authorized user's client along to the
 	// store which will use the user's credentials
 	// on each request.
-	c := &client{host: orderHost, Client: u.auth.cli.Client}
+	c := &client{host: u.auth.cli.host, market: u.auth.cli.market, Client: u.auth.cli.Client}
 	if err = u.addressCheck(); err != nil {
 		return nil, err
 	}
@@ -124,11 +148,10 @@ func (u *UserProfile) DefaultAddress() *UserAddress {
 	return u.Addresses[0]
 }
 
-// SetServiceMethod will set the user's default service method,
-// should be "Delivery" or "Carryout"
-func (u *UserProfile) SetServiceMethod(service string) error {
-	if !(service == Delivery || service == Carryout) {
-		return ErrBadService
+// SetServiceMethod will set the user's default service method.
+func (u *UserProfile) SetServiceMethod(service ServiceMethod) error {
+	if err := service.Valid(); err != nil {
+		return err
 	}
 	u.ServiceMethod = service
 	return nil
@@ -170,7 +193,10 @@ func (u *UserProfile) getLoyalty() (*CustomerLoyalty, error) {
 
 // PreviousOrders will return `n` of the user's previous orders.
 func (u *UserProfile) PreviousOrders(n int) ([]*EasyOrder, error) {
-	return u.ordersMeta.CustomerOrders, u.initOrdersMeta(n)
+	if err := u.initOrdersMeta(n); err != nil {
+		return nil, err
+	}
+	return u.ordersMeta.CustomerOrders, nil
 }
 
 // GetEasyOrder will return the user's easy order.
@@ -189,7 +215,7 @@ func (u *UserProfile) initOrdersMeta(limit int) error {
 	u.ordersMeta = &customerOrders{}
 	return u.customerEndpoint(
 		"order",
-		Params{"limit": limit, "lang": DefaultLang},
+		Params{"limit": limit, "lang": u.auth.cli.getMarket().Lang},
 		&u.ordersMeta,
 	)
 }
@@ -207,7 +233,7 @@ func (u *UserProfile) NewOrder() (*Order, error) {
 		FirstName:     u.FirstName,
 		LastName:      u.LastName,
 		Email:         u.Email,
-		LanguageCode:  DefaultLang,
+		LanguageCode:  u.auth.cli.getMarket().Lang,
 		ServiceMethod: u.ServiceMethod,
 		StoreID:       u.store.ID,
 		CustomerID:    u.CustomerID,
@@ -216,6 +242,7 @@ func (u *UserProfile) NewOrder() (*Order, error) {
 		Address:       StreetAddrFromAddress(u.store.userAddress),
 		Payments:      []*orderPayment{},
 		cli:           u.auth.cli,
+		user:          u,
 	}
 	return order, nil
 }
@@ -241,26 +268,43 @@ func (u *UserProfile) customerEndpoint(
 	path string,
 	params Params,
 	obj interface{},
+) error {
+	return u.customerRequest("GET", path, params, nil, obj)
+}
+
+func (u *UserProfile) customerRequest(
+	method, path string,
+	params Params,
+	body io.Reader,
+	obj interface{},
 ) error {
 	if u.CustomerID == "" {
 		return errors.New("UserProfile not fully initialized: needs CustomerID")
 	}
+	if u.auth == nil {
+		return errors.New("UserProfile is not signed in")
+	}
 	if params == nil {
 		params = make(Params)
 	}
 	params["_"] = time.Now().Nanosecond()
 
-	return u.auth.cli.dojson(obj, &http.Request{
-		Method: "GET",
+	req := &http.Request{
+		Method: method,
 		Proto:  "HTTP/1.1",
 		Header: make(http.Header),
 		URL: &url.URL{
 			Scheme:   "https",
 			Host:     u.auth.cli.host,
-			Path:     fmt.Sprintf("/power/customer/%s/%s", u.CustomerID, path),
+			Path:     strings.TrimSuffix("/power/customer/"+u.CustomerID+"/"+path, "/"),
 			RawQuery: params.Encode(),
 		},
-	})
+	}
+	if body != nil {
+		req.Body = ioutil.NopCloser(body)
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return u.auth.cli.dojson(obj, req)
 }
 
 // UserAddress is an address that is saved by dominos and returned when
@@ -302,18 +346,11 @@ type UserAddress struct {
 	Coordinates     map[string]float32
 }
 
-var _ Address = (*UserAddress)(nil)
+var _ DetailedAddress = (*UserAddress)(nil)
 
 // UserAddressFromAddress converts an address to a UserAddress.
 func UserAddressFromAddress(a Address) *UserAddress {
-	var streetNum, streetName string
-	parts := strings.Split(a.LineOne(), " ")
-
-	if _, err := strconv.Atoi(parts[0]); err == nil {
-		streetNum = parts[0]
-	}
-	streetName = strings.Join(parts[1:], " ")
-
+	streetNum, streetName := splitStreet(a.LineOne())
 	if addr, ok := a.(*UserAddress); ok {
 		if len(addr.StreetNumber) == 0 {
 			addr.StreetNumber = streetNum
@@ -324,7 +361,7 @@ func UserAddressFromAddress(a Address) *UserAddress {
 		return addr
 	}
 
-	return &UserAddress{
+	ua := &UserAddress{
 		Street:       a.LineOne(),
 		StreetNumber: streetNum,
 		StreetName:   streetName,
@@ -332,6 +369,26 @@ func UserAddressFromAddress(a Address) *UserAddress {
 		PostalCode:   a.Zip(),
 		Region:       a.StateCode(),
 	}
+	if details, ok := a.(DetailedAddress); ok {
+		ua.UnitType, ua.UnitNumber = details.Unit()
+		ua.DeliveryInstructions = details.Instructions()
+	}
+	return ua
+}
+
+// SetStreet changes the first line of the address and splits it into the
+// street number and street name again.
+func (ua *UserAddress) SetStreet(street string) {
+	ua.Street = street
+	ua.StreetNumber, ua.StreetName = splitStreet(street)
+}
+
+func splitStreet(street string) (number, name string) {
+	parts := strings.Split(street, " ")
+	if _, err := strconv.Atoi(parts[0]); err == nil {
+		number = parts[0]
+	}
+	return number, strings.Join(parts[1:], " ")
 }
 
 // LineOne returns the first line of the address.
@@ -357,6 +414,16 @@ func (ua *UserAddress) Zip() string {
 	return ua.PostalCode
 }
 
+// Unit returns the unit type and number of the address.
+func (ua *UserAddress) Unit() (unitType, unitNumber string) {
+	return ua.UnitType, ua.UnitNumber
+}
+
+// Instructions returns the delivery instructions saved with the address.
+func (ua *UserAddress) Instructions() string {
+	return ua.DeliveryInstructions
+}
+
 // UserCard holds the card data that Dominos stores and send back to users.
 // For security reasons, Dominos does not send the raw card number or the
 // raw security code. Insted they send a card ID that is used to reference
@@ -401,12 +468,15 @@ type CustomerLoyalty struct {
 	VestedPointBalance int
 	// This is a list of possible coupons that a
 	// customer can receive.
-	LoyaltyCoupons []struct {
-		CouponCode    string
-		PointValue    int
-		BaseCoupon    bool
-		LimitPerOrder string
-	}
+	LoyaltyCoupons []LoyaltyCoupon
+}
+
+// LoyaltyCoupon is a coupon that can be redeemed using loyalty points.
+type LoyaltyCoupon struct {
+	CouponCode    string
+	PointValue    int
+	BaseCoupon    bool
+	LimitPerOrder string
 }
 
 // TODO: figure out how the dominos website sends an easy order to the servers
@@ -447,6 +517,86 @@ type EasyOrder struct {
 	Order previousOrder `json:"order"`
 }
 
+// ToOrder rebuilds the easy order as a new order that can be sent to the
+// store given. The products are looked up in the store's current menu and
+// any that are no longer offered are left out of the order and reported
+// with an *ItemsNotFoundError. When this error is returned, the order is
+// still usable.
+//
+// The address and delivery instructions are carried over from the previous
+// order. If the previous order has no address, the store's address is used.
+func (eo *EasyOrder) ToOrder(store *Store) (*Order, error) {
+	if store == nil {
+		return nil, errors.New("EasyOrder.ToOrder: nil store")
+	}
+	menu, err := store.Menu()
+	if err != nil {
+		return nil, err
+	}
+	prev := &eo.Order
+	order := &Order{
+		CustomerID:    prev.CustomerID,
+		FirstName:     prev.FirstName,
+		LastName:      prev.LastName,
+		Email:         prev.Email,
+		Phone:         prev.Phone,
+		LanguageCode:  store.Market().Lang,
+		ServiceMethod: prev.ServiceMethod,
+		StoreID:       store.ID,
+		Products:      []*OrderProduct{},
+		Payments:      []*orderPayment{},
+		OrderName:     eo.OrderNickName,
+		cli:           store.cli,
+	}
+	if order.ServiceMethod == "" {
+		order.ServiceMethod = store.userService
+	}
+	if order.cli == nil {
+		order.cli = orderClient
+	}
+
+	if prev.Address != nil {
+		addr := *prev.Address
+		order.Address = &addr
+	} else if store.userAddress != nil {
+		order.Address = StreetAddrFromAddress(store.userAddress)
+	}
+	if order.Address != nil && eo.DeliveryInstructions != "" {
+		order.Address.DeliveryInstructions = eo.DeliveryInstructions
+	}
+
+	missing := &ItemsNotFoundError{}
+	for _, p := range prev.Products {
+		item := menu.FindItem(p.Code)
+		if item == nil {
+			missing.Codes = append(missing.Codes, p.Code)
+			continue
+		}
+		product := OrderProductFromItem(item)
+		if p.Qty > 0 {
+			product.Qty = p.Qty
+		}
+		if len(p.Opts) > 0 {
+			product.Opts = p.Opts
+		}
+		order.Products = append(order.Products, product)
+	}
+	if len(missing.Codes) > 0 {
+		return order, missing
+	}
+	return order, nil
+}
+
+// ItemsNotFoundError is returned when items could not be found on the menu.
+type ItemsNotFoundError struct {
+	// Codes are the item codes that were not found.
+	Codes []string
+}
+
+func (e *ItemsNotFoundError) Error() string {
+	return fmt.Sprintf("no longer on the menu: %s", strings.Join(e.Codes, ", "))
+}
+
 type previousOrder struct {
 	Order
 	pricedOrder
@@ -1,6 +1,10 @@
 package dawg
 
 import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
 	"testing"
 
 	"github.com/harrybrwn/apizza/pkg/tests"
@@ -97,7 +101,7 @@ func TestUserProfile_StoresNearMe(t *testing.T) {
 		if s.userAddress == nil {
 			t.Fatal("nil store.userAddress")
 		}
-		tests.StrEq(s.userService, user.ServiceMethod, "wrong service method")
+		tests.StrEq(string(s.userService), string(user.ServiceMethod), "wrong service method")
 		tests.StrEq(s.userAddress.City(), addr.City(), "wrong city")
 		tests.StrEq(s.userAddress.LineOne(), addr.LineOne(), "wrong line one")
 		tests.StrEq(s.userAddress.StateCode(), addr.StateCode(), "wrong state code")
@@ -122,8 +126,8 @@ func TestUserProfile_NewOrder(t *testing.T) {
 	order, err := user.NewOrder()
 	tests.Check(err)
 
-	tests.StrEq(order.ServiceMethod, Carryout, "wrong service method")
-	tests.StrEq(order.ServiceMethod, user.ServiceMethod, "service method should carry over from the user")
+	tests.StrEq(string(order.ServiceMethod), string(Carryout), "wrong service method")
+	tests.StrEq(string(order.ServiceMethod), string(user.ServiceMethod), "service method should carry over from the user")
 	tests.StrEq(order.Phone, user.Phone, "phone should carry over from user")
 	tests.StrEq(order.FirstName, user.FirstName, "first name should carry over from user")
 	tests.StrEq(order.LastName, user.LastName, "last name should carry over from user")
@@ -134,3 +138,115 @@ func TestUserProfile_NewOrder(t *testing.T) {
 		t.Error("order should get and address from the user")
 	}
 }
+
+func TestEasyOrder_ToOrder(t *testing.T) {
+	tests.InitHelpers(t)
+	store := &Store{ID: "4336", userService: Carryout}
+	store.menu = &Menu{
+		ID:       store.ID,
+		Products: map[string]*Product{"S_PIZZA": {ItemCommon: ItemCommon{Code: "S_PIZZA"}, ProductType: "Pizza"}},
+		Variants: map[string]*Variant{
+			"14SCREEN": {ItemCommon: ItemCommon{Code: "14SCREEN", Name: "Large Pizza"}, ProductCode: "S_PIZZA"},
+		},
+	}
+	eo := &EasyOrder{OrderNickName: "usual", DeliveryInstructions: "ring the bell"}
+	eo.Order.ServiceMethod = Delivery
+	eo.Order.Address = testAddress()
+	eo.Order.Products = []*OrderProduct{
+		{ItemCommon: ItemCommon{Code: "14SCREEN"}, Qty: 2,
+			Opts: map[string]interface{}{"P": map[string]string{ToppingFull: "1.0"}}},
+		{ItemCommon: ItemCommon{Code: "OLDTHING"}, Qty: 1},
+	}
+
+	order, err := eo.ToOrder(store)
+	if order == nil {
+		t.Fatal("should still get an order when items are missing")
+	}
+	missing, ok := err.(*ItemsNotFoundError)
+	if !ok {
+		t.Fatalf("expected an *ItemsNotFoundError; got %T", err)
+	}
+	if len(missing.Codes) != 1 || missing.Codes[0] != "OLDTHING" {
+		t.Error("wrong missing items:", missing.Codes)
+	}
+	if len(order.Products) != 1 {
+		t.Fatal("wrong number of products")
+	}
+	p := order.Products[0]
+	tests.StrEq(p.Code, "14SCREEN", "wrong product code")
+	tests.StrEq(p.Name, "Large Pizza", "product should be rebuilt from the menu")
+	if p.Qty != 2 {
+		t.Error("quantity should carry over")
+	}
+	if _, ok := p.Opts["P"]; !ok {
+		t.Error("toppings should carry over")
+	}
+	tests.StrEq(order.Name(), "usual", "wrong order name")
+	tests.StrEq(order.StoreID, store.ID, "wrong store id")
+	tests.StrEq(string(order.ServiceMethod), string(Delivery), "service should carry over")
+	tests.StrEq(order.Address.LineOne(), eo.Order.Address.LineOne(), "address should carry over")
+	tests.StrEq(order.Address.DeliveryInstructions, "ring the bell", "delivery instructions should carry over")
+	if eo.Order.Address.DeliveryInstructions != "" {
+		t.Error("should not change the easy order's address")
+	}
+
+	eo.Order.Products = eo.Order.Products[:1]
+	_, err = eo.ToOrder(store)
+	tests.Check(err)
+	_, err = eo.ToOrder(nil)
+	tests.Exp(err)
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestUserProfile_Update(t *testing.T) {
+	tests.InitHelpers(t)
+	var sent UserProfile
+	user := &UserProfile{FirstName: "Jane", CustomerID: "12345"}
+	user.auth = &auth{cli: &client{
+		host: orderHost,
+		Client: &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			tests.StrEq(r.Method, "POST", "profile updates should be POSTed")
+			tests.StrEq(r.URL.Path, "/power/customer/12345", "wrong update path")
+			tests.Check(json.NewDecoder(r.Body).Decode(&sent))
+			resp := sent
+			resp.UpdateTime = "2026-10-18 12:00:00"
+			body, err := json.Marshal(&resp)
+			tests.Check(err)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(body)),
+			}, nil
+		})},
+	}}
+
+	addr := user.AddAddress(&StreetAddr{
+		Street: "1600 Pennsylvania Ave NW", CityName: "Washington", State: "DC", Zipcode: "20500",
+		DeliveryInstructions: "use the side door",
+	})
+	addr.Name = "work"
+	user.AddAddress(testAddress()).Name = "home"
+	tests.Exp(user.SetDefaultAddress("nowhere"))
+	tests.Check(user.SetDefaultAddress("work"))
+	user.SmsOptIn = true
+
+	tests.Check(user.Update())
+	if len(sent.Addresses) != 2 {
+		t.Fatal("addresses should be sent in the update")
+	}
+	tests.StrEq(sent.Addresses[0].DeliveryInstructions, "use the side door", "delivery instructions should be sent")
+	if !sent.Addresses[0].IsDefault || sent.Addresses[1].IsDefault {
+		t.Error("only the work address should be the default")
+	}
+	if !sent.SmsOptIn {
+		t.Error("sms opt-in should be sent")
+	}
+	tests.StrEq(user.UpdateTime, "2026-10-18 12:00:00", "profile should be updated from the response")
+	if user.auth == nil {
+		t.Error("update should not lose the user's auth")
+	}
+
+	tests.Exp((&UserProfile{CustomerID: "1"}).Update(), "should not update a profile that is not signed in")
+}
@@ -69,6 +69,9 @@ type roundTripper struct {
 }
 
 func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := checkOffline(); err != nil {
+		return nil, err
+	}
 	err := rt.f(req)
 	if err != nil {
 		return nil, err
@@ -1,6 +1,6 @@
 module github.com/harrybrwn/apizza
 
-go 1.13
+go 1.17
 
 require (
 	github.com/boltdb/bolt v1.3.1
@@ -8,5 +8,12 @@ require (
 	github.com/mitchellh/mapstructure v1.2.2
 	github.com/spf13/cobra v0.0.7
 	github.com/spf13/pflag v1.0.5
+	golang.org/x/crypto v0.8.0
+	golang.org/x/term v0.10.0
 	gopkg.in/natefinch/lumberjack.v2 v2.0.0
 )
+
+require (
+	github.com/inconshreveable/mousetrap v1.0.0 // indirect
+	golang.org/x/sys v0.10.0 // indirect
+)
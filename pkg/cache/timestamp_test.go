@@ -48,6 +48,12 @@ func TestTimeStamp(t *testing.T) {
 	if time.Since(stampRe) > t2 {
 		t.Error("timestamp didn't reset")
 	}
+	if err = db.DeleteTimeStamp("test"); err != nil {
+		t.Error(err)
+	}
+	if db.Exists(ts("test")) {
+		t.Error("timestamp should have been deleted")
+	}
 	if err = db.Destroy(); err != nil {
 		t.Error(err)
 	}
@@ -0,0 +1,81 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", s, time.UTC)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestNext(t *testing.T) {
+	tt := []struct {
+		spec, from, next string
+	}{
+		{"0 12 * * FRI", "2026-10-18 09:00", "2026-10-23 12:00"},
+		{"0 12 * * fri", "2026-10-23 12:00", "2026-10-30 12:00"},
+		{"*/15 * * * *", "2026-10-18 09:07", "2026-10-18 09:15"},
+		{"30 9-17/4 * * *", "2026-10-18 14:00", "2026-10-18 17:30"},
+		{"0 0 1 JAN-MAR *", "2026-10-18 00:00", "2027-01-01 00:00"},
+		{"0 18 13 * 5", "2026-10-18 00:00", "2026-10-23 18:00"}, // day of month or day of week
+		{"0 0 * * 7", "2026-10-18 00:00", "2026-10-25 00:00"},
+		{"@daily", "2026-10-18 23:59", "2026-10-19 00:00"},
+		{"@monthly", "2026-12-05 10:00", "2027-01-01 00:00"},
+		{"0 0 29 2 *", "2026-10-18 00:00", "2028-02-29 00:00"},
+		{"0 0 30 2 *", "2026-10-18 00:00", ""},
+	}
+	for _, tc := range tt {
+		s, err := Parse(tc.spec)
+		if err != nil {
+			t.Errorf("%q: %v", tc.spec, err)
+			continue
+		}
+		next := s.Next(date(tc.from))
+		if tc.next == "" {
+			if !next.IsZero() {
+				t.Errorf("%q should never match; got %v", tc.spec, next)
+			}
+			continue
+		}
+		if !next.Equal(date(tc.next)) {
+			t.Errorf("%q from %s: expected %s, got %s", tc.spec, tc.from, tc.next, next.Format("2006-01-02 15:04"))
+		}
+	}
+}
+
+func TestBetween(t *testing.T) {
+	s, err := Parse("0 12 * * FRI")
+	if err != nil {
+		t.Fatal(err)
+	}
+	times := s.Between(date("2026-10-01 00:00"), date("2026-10-23 12:00"))
+	if len(times) != 4 {
+		t.Fatalf("expected 4 fridays; got %d", len(times))
+	}
+	if !times[3].Equal(date("2026-10-23 12:00")) {
+		t.Error("the end should be included")
+	}
+	if len(s.Between(date("2026-10-23 12:00"), date("2026-10-23 12:00"))) != 0 {
+		t.Error("the start should not be included")
+	}
+	if s.String() != "0 12 * * FRI" {
+		t.Error("wrong string")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"", "* * * *", "* * * * * *", "60 * * * *", "* 24 * * *", "* * 0 * *",
+		"* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "a * * * *",
+		"* * * FOO *", "@often",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("expected an error for %q", spec)
+		}
+	}
+}